@@ -37,6 +37,7 @@ import (
 	"github.com/vhive-serverless/loader/pkg/common"
 	"github.com/vhive-serverless/loader/pkg/config"
 	"github.com/vhive-serverless/loader/pkg/driver"
+	"github.com/vhive-serverless/loader/pkg/sampler"
 	"github.com/vhive-serverless/loader/pkg/trace"
 
 	log "github.com/sirupsen/logrus"
@@ -76,6 +77,19 @@ func init() {
 }
 
 func main() {
+	if flag.NArg() > 0 {
+		switch flag.Arg(0) {
+		case "sample":
+			runSampleCommand(flag.Args()[1:])
+		case "validate-trace":
+			runValidateTraceCommand(flag.Args()[1:])
+		default:
+			log.Fatalf("Unknown command %s. Supported commands: [sample, validate-trace]", flag.Arg(0))
+		}
+
+		return
+	}
+
 	cfg := config.ReadConfigurationFile(*configPath)
 	if cfg.EnableZipkinTracing {
 		// TODO: how not to exclude Zipkin spans here? - file a feature request
@@ -106,6 +120,25 @@ func main() {
 		common.CheckCPULimit(cfg.CPULimit)
 	}
 
+	if cfg.AppLevelDeployment {
+		if !slices.Contains([]string{"Knative", "Dirigent", "Dirigent-Dandelion"}, cfg.Platform) {
+			log.Fatal("App-level deployment is supported only on Knative and Dirigent.")
+		}
+		if cfg.AppFunctionSelector == "" {
+			cfg.AppFunctionSelector = "header"
+		} else if cfg.AppFunctionSelector != "header" && cfg.AppFunctionSelector != "path" {
+			log.Fatal("Invalid 'AppFunctionSelector' parameter.")
+		} else if cfg.AppFunctionSelector == "path" && cfg.Platform == "Dirigent-Dandelion" {
+			log.Fatal("AppFunctionSelector 'path' is not supported on Dirigent-Dandelion, whose invocations use a fixed URL path.")
+		} else if cfg.AppFunctionSelector == "path" && cfg.InvokeProtocol == "grpc" {
+			log.Fatal("AppFunctionSelector 'path' is supported only with HTTP invocations, as gRPC invocations select the function by the metadata header.")
+		}
+	}
+
+	if cfg.MultiTenant && cfg.Platform != "Knative" {
+		log.Warnf("Namespaces and quotas of tenants are supported only on Knative. Tenants are used only for reporting on %s.", cfg.Platform)
+	}
+
 	if cfg.TracePath == "RPS" {
 		runRPSMode(&cfg, *iatFromFile, *iatGeneration)
 	} else {
@@ -137,6 +170,26 @@ func parseIATDistribution(cfg *config.LoaderConfiguration) (common.IatDistributi
 		return common.Uniform, true
 	case "equidistant":
 		return common.Equidistant, false
+	case "gamma":
+		return common.Gamma, false
+	case "gamma_shift":
+		return common.Gamma, true
+	case "weibull":
+		return common.Weibull, false
+	case "weibull_shift":
+		return common.Weibull, true
+	case "lognormal":
+		return common.Lognormal, false
+	case "lognormal_shift":
+		return common.Lognormal, true
+	case "pareto":
+		return common.Pareto, false
+	case "pareto_shift":
+		return common.Pareto, true
+	case "empirical":
+		return common.Empirical, false
+	case "empirical_shift":
+		return common.Empirical, true
 	default:
 		log.Fatal("Unsupported IAT distribution.")
 	}
@@ -176,13 +229,8 @@ func runTraceMode(cfg *config.LoaderConfiguration, readIATFromFile bool, writeIA
 	durationToParse := determineDurationToParse(cfg.ExperimentDuration, cfg.WarmupDuration)
 	yamlPath := parseYAMLSpecification(cfg)
 
-	// Azure trace parsing
-	traceParser := trace.NewAzureParser(cfg.TracePath, durationToParse)
-	functions := traceParser.Parse()
-
-	// Dirigent metadata parsing
-	dirigentMetadataParser := trace.NewDirigentMetadataParser(cfg.TracePath, functions, yamlPath, cfg.Platform)
-	dirigentMetadataParser.Parse()
+	functions := trace.ParseTrace(cfg, durationToParse, parseTraceGranularity(cfg),
+		trace.NewMetadataEnrichers(cfg.TracePath, yamlPath, cfg.Platform)...)
 
 	log.Infof("Traces contain the following %d functions:\n", len(functions))
 	for _, function := range functions {
@@ -227,8 +275,20 @@ func runRPSMode(cfg *config.LoaderConfiguration, readIATFromFile bool, writeIATs
 	warmStartRPS := rpsTarget * (100 - coldStartPercentage) / 100
 	coldStartRPS := rpsTarget * coldStartPercentage / 100
 
-	warmFunction, warmStartCount := generator.GenerateWarmStartFunction(experimentDuration, warmStartRPS)
-	coldFunctions, coldStartCount := generator.GenerateColdStartFunctions(experimentDuration, coldStartRPS, cfg.RpsCooldownSeconds)
+	var warmFunction common.IATArray
+	var warmStartCount []int
+	var coldFunctions []common.IATArray
+	var coldStartCount [][]int
+
+	if cfg.RpsProfile != "" {
+		profile := generator.NewRPSProfile(cfg, experimentDuration)
+
+		warmFunction, warmStartCount = generator.GenerateWarmStartFunctionByProfile(experimentDuration, profile.Scale((100-coldStartPercentage)/100))
+		coldFunctions, coldStartCount = generator.GenerateColdStartFunctionsByProfile(experimentDuration, profile.Scale(coldStartPercentage/100), cfg.RpsCooldownSeconds)
+	} else {
+		warmFunction, warmStartCount = generator.GenerateWarmStartFunction(experimentDuration, warmStartRPS)
+		coldFunctions, coldStartCount = generator.GenerateColdStartFunctions(experimentDuration, coldStartRPS, cfg.RpsCooldownSeconds)
+	}
 
 	experimentDriver := driver.NewDriver(&config.Configuration{
 		LoaderConfiguration: cfg,
@@ -247,3 +307,45 @@ func runRPSMode(cfg *config.LoaderConfiguration, readIATFromFile bool, writeIATs
 	experimentDriver.ReadOrWriteFileSpecification(writeIATsToFile, readIATFromFile)
 	experimentDriver.RunExperiment()
 }
+
+func runSampleCommand(args []string) {
+	flags := flag.NewFlagSet("sample", flag.ExitOnError)
+	tracePath := flags.String("tracePath", "data/traces/", "Path to the directory with the Azure trace to sample from")
+	outputPath := flags.String("outputPath", "data/traces/sample", "Directory to write the sampled trace to")
+	sampleSize := flags.Int("sampleSize", 100, "Number of functions in the sample")
+	duration := flags.Int("duration", trace.MinutesPerDay, "Number of minutes of the trace to read")
+	bins := flags.Int("bins", 4, "Number of quantile bins per feature used for stratification")
+	seed := flags.Int64("seed", 42, "Seed for the selection of functions within strata")
+	yamlPath := flags.String("yaml", "workloads/container/trace_func_go.yaml", "Service YAML used as Dirigent metadata of functions missing from dirigent.json")
+	_ = flags.Parse(args)
+
+	functions := trace.NewAzureParser(*tracePath, *duration).Parse()
+	sampler.AttachDirigentMetadata(functions, *tracePath, *yamlPath)
+
+	sample := sampler.StratifiedSample(functions, *sampleSize, *bins, *seed)
+	sampler.WriteTrace(sample, *outputPath)
+
+	log.Infof("Sampled %d out of %d functions into %s", len(sample), len(functions), *outputPath)
+	for _, distance := range sampler.ComputeDistances(functions, sample) {
+		log.Infof("Wasserstein distance of %s: %.4f (normalized: %.4f)", distance.Feature, distance.Wasserstein, distance.Normalized)
+	}
+}
+
+func runValidateTraceCommand(args []string) {
+	flags := flag.NewFlagSet("validate-trace", flag.ExitOnError)
+	tracePath := flags.String("tracePath", "data/traces/", "Path to the directory with the trace to validate")
+	duration := flags.Int("duration", 0, "Duration of the experiment in minutes to check against the trace (ignored if zero)")
+	outputPath := flags.String("output", "trace_validation.json", "Path of the JSON report")
+	_ = flags.Parse(args)
+
+	report := trace.ValidateTrace(*tracePath, *duration)
+	report.WriteText(os.Stdout)
+
+	if err := report.WriteJSON(*outputPath); err != nil {
+		log.Fatalf("Failed to write the validation report - %v", err)
+	}
+
+	if report.HasErrors() {
+		os.Exit(1)
+	}
+}
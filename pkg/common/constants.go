@@ -54,6 +54,11 @@ const (
 	Exponential IatDistribution = iota
 	Uniform
 	Equidistant
+	Gamma
+	Weibull
+	Lognormal
+	Pareto
+	Empirical
 )
 
 type TraceGranularity int
@@ -63,6 +68,18 @@ const (
 	SecondGranularity
 )
 
+// JoinPolicy determines how a DAG continues at a node with several predecessors when some of them failed
+type JoinPolicy int
+
+const (
+	// JoinFail invokes neither the join nor the nodes after it
+	JoinFail JoinPolicy = iota
+	// JoinSkip skips the join and continues with the nodes after it
+	JoinSkip
+	// JoinPartial invokes the join as long as one of its predecessors succeeded
+	JoinPartial
+)
+
 type ExperimentPhase int
 
 const (
@@ -24,6 +24,11 @@
 
 package common
 
+import (
+	"math"
+	"math/rand"
+)
+
 // IATArray Hold the IATs of invocations for a particular function. Values in this array tells individual function driver
 // how much time to sleep before firing an invocation. First invocations should be fired right away after the start of
 // experiment, i.e., should typically have a IAT of 0.
@@ -35,6 +40,51 @@ type ProbabilisticDuration []float64
 type RuntimeSpecification struct {
 	Runtime int
 	Memory  int
+	// size of the data passed by the predecessors of a node of a DAG, determined when the node is invoked
+	PayloadBytes int `json:"-"`
+}
+
+type PayloadDistribution int
+
+const (
+	ConstantPayload PayloadDistribution = iota
+	UniformPayload
+	ExponentialPayload
+	LognormalPayload
+)
+
+// PayloadSize is the distribution of the size in bytes of the data passed along an edge of a DAG
+type PayloadSize struct {
+	Distribution PayloadDistribution
+	MeanBytes    float64
+	// bounds of the sizes if positive
+	MinBytes float64
+	MaxBytes float64
+	// standard deviation of the logarithm of lognormal sizes
+	Sigma float64
+}
+
+// Sample draws a payload size in bytes from the given source
+func (p *PayloadSize) Sample(r *rand.Rand) int {
+	var size float64
+	switch p.Distribution {
+	case UniformPayload:
+		size = p.MinBytes + r.Float64()*(p.MaxBytes-p.MinBytes)
+	case ExponentialPayload:
+		size = r.ExpFloat64() * p.MeanBytes
+	case LognormalPayload:
+		// the location is chosen for the mean of the distribution to be MeanBytes
+		size = math.Exp(math.Log(p.MeanBytes) - p.Sigma*p.Sigma/2 + p.Sigma*r.NormFloat64())
+	default:
+		size = p.MeanBytes
+	}
+
+	size = math.Max(size, p.MinBytes)
+	if p.MaxBytes > 0 {
+		size = math.Min(size, p.MaxBytes)
+	}
+
+	return int(math.Round(size))
 }
 
 type RuntimeSpecificationArray []RuntimeSpecification
@@ -33,6 +33,36 @@ type FunctionInvocationStats struct {
 	Trigger      string
 
 	Invocations []int
+	// Windows reads the invocations lazily from a streamed trace, in which case Invocations is nil
+	Windows InvocationWindowReader `json:"-"`
+}
+
+// InvocationWindowReader reads the invocations per time unit of a function from the trace on demand, so that the
+// invocations of the whole trace do not have to be kept in memory
+type InvocationWindowReader interface {
+	// TimeUnits returns the number of time units of the trace
+	TimeUnits() int
+	// ReadWindow returns the invocations in the time units [start, end)
+	ReadWindow(start int, end int) []int
+}
+
+// TimeUnits returns the number of time units of the trace of the function
+func (s *FunctionInvocationStats) TimeUnits() int {
+	if s.Windows != nil {
+		return s.Windows.TimeUnits()
+	}
+
+	return len(s.Invocations)
+}
+
+// InvocationWindow returns the invocations in the time units [start, end), which are read from the trace if it is
+// streamed
+func (s *FunctionInvocationStats) InvocationWindow(start int, end int) []int {
+	if s.Windows != nil {
+		return s.Windows.ReadWindow(start, end)
+	}
+
+	return s.Invocations[start:end]
 }
 
 type FunctionRuntimeStats struct {
@@ -88,6 +118,10 @@ type DirigentMetadata struct {
 type Function struct {
 	Name     string
 	Endpoint string
+	// AppService is the service of the HashApp the function is deployed in with app-level deployment
+	AppService string
+	// Tenant is the namespace of the tenant the owner of the function is assigned to in multi-tenant experiments
+	Tenant string
 
 	// From the static trace profiler
 	InitialScale int
@@ -106,9 +140,36 @@ type Function struct {
 	Specification *FunctionSpecification
 }
 
+// ServiceName returns the name of the service the function is deployed as
+func (f *Function) ServiceName() string {
+	if f.AppService != "" {
+		return f.AppService
+	}
+
+	return f.Name
+}
+
 type Node struct {
 	Function *Function
 	Branches []*list.List
 	Depth    int
 	DAG      string
+	// number of nodes that have to complete before the node is invoked. A node with several predecessors is a join,
+	// which is the front of a branch shared by the Branches of all its predecessors.
+	Predecessors int
+	// name of the node within its DAG, the name of its function if empty
+	Stage string
+	// size of the data each predecessor passes to the node, nil if none
+	Payload *PayloadSize
+	// sizes of the data each predecessor passes to the node in each invocation of the DAG, sampled before the experiment
+	PayloadSizes [][]int
+}
+
+// StageName returns the name of the node within its DAG
+func (n *Node) StageName() string {
+	if n.Stage != "" {
+		return n.Stage
+	}
+
+	return n.Function.Name
 }
@@ -0,0 +1,122 @@
+package common
+
+import (
+	"encoding/json"
+	"time"
+
+	ctrdlog "github.com/containerd/log"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// VHiveMetadataKey is the gRPC metadata header carrying the vHive metadata of trace function invocations
+	VHiveMetadataKey = "vhive-metadata"
+	// ChainHopsKey is the gRPC trailer carrying the records of the stages invoked by a trace function
+	ChainHopsKey = "vhive-chain-hops"
+)
+
+type VHiveMetadata struct {
+	WorkflowId   string    `json:"WorkflowId"`
+	InvocationId string    `json:"InvocationId"`
+	InvokedOn    time.Time `json:"InvokedOn"`
+
+	// stage of the DAG the invoked function executes and the stages it invokes itself in server-side chaining
+	Stage string          `json:"Stage,omitempty"`
+	Next  []*ChainedStage `json:"Next,omitempty"`
+}
+
+// ChainedStage is a stage of a DAG that is invoked by the function of its predecessor in server-side chaining
+type ChainedStage struct {
+	Stage     string `json:"Stage"`
+	Endpoint  string `json:"Endpoint"`
+	Authority string `json:"Authority,omitempty"`
+
+	RuntimeInMilliSec uint32 `json:"RuntimeInMilliSec"`
+	MemoryInMebiBytes uint32 `json:"MemoryInMebiBytes"`
+	PayloadBytes      int    `json:"PayloadBytes,omitempty"`
+
+	Next []*ChainedStage `json:"Next,omitempty"`
+}
+
+// ChainHop is the record of the invocation of a stage by the function of its predecessor in server-side chaining
+type ChainHop struct {
+	Stage       string `json:"Stage"`
+	ParentStage string `json:"ParentStage"`
+	Success     bool   `json:"Success"`
+
+	// Measurements in microseconds, where StartTime is a timestamp
+	StartTime          int64  `json:"StartTime"`
+	ResponseTime       int64  `json:"ResponseTime"`
+	DurationInMicroSec uint32 `json:"DurationInMicroSec"`
+
+	MemoryUsageInKb uint32 `json:"MemoryUsageInKb"`
+	PayloadBytes    int    `json:"PayloadBytes,omitempty"`
+	Message         string `json:"Message"`
+}
+
+func GetWorkflowId(d []byte) string {
+	return unmarshalVHiveMetadata(d).WorkflowId
+}
+
+func GetInvocationId(d []byte) string {
+	return unmarshalVHiveMetadata(d).InvocationId
+}
+
+func GetInvokedOn(d []byte) time.Time {
+	return unmarshalVHiveMetadata(d).InvokedOn
+}
+
+func unmarshalVHiveMetadata(d []byte) (vhm VHiveMetadata) {
+	if err := json.Unmarshal(d, &vhm); err != nil {
+		logrus.Fatal("failed to unmarshal vhivemetadata", err)
+	}
+	return
+}
+
+// ParseVHiveMetadata unmarshals vHive metadata received by a function, which must not terminate on malformed input
+func ParseVHiveMetadata(d []byte) (VHiveMetadata, error) {
+	var vhm VHiveMetadata
+	err := json.Unmarshal(d, &vhm)
+
+	return vhm, err
+}
+
+func MakeVHiveMetadata(WorkflowId, InvocationId string, InvokedOn time.Time) []byte {
+	return marshalVHiveMetadata(VHiveMetadata{
+		WorkflowId:   WorkflowId,
+		InvocationId: InvocationId,
+		InvokedOn:    InvokedOn,
+	})
+}
+
+// MakeChainedVHiveMetadata marshals the vHive metadata of the invocation of a stage of a DAG, whose function invokes
+// the next stages itself
+func MakeChainedVHiveMetadata(WorkflowId, InvocationId string, InvokedOn time.Time, stage string, next []*ChainedStage) []byte {
+	return marshalVHiveMetadata(VHiveMetadata{
+		WorkflowId:   WorkflowId,
+		InvocationId: InvocationId,
+		InvokedOn:    InvokedOn,
+		Stage:        stage,
+		Next:         next,
+	})
+}
+
+func marshalVHiveMetadata(vhm VHiveMetadata) []byte {
+	d, err := json.Marshal(struct {
+		WorkflowId   string          `json:"WorkflowId"`
+		InvocationId string          `json:"InvocationId"`
+		InvokedOn    string          `json:"InvokedOn"`
+		Stage        string          `json:"Stage,omitempty"`
+		Next         []*ChainedStage `json:"Next,omitempty"`
+	}{
+		WorkflowId:   vhm.WorkflowId,
+		InvocationId: vhm.InvocationId,
+		InvokedOn:    vhm.InvokedOn.Format(ctrdlog.RFC3339NanoFixed),
+		Stage:        vhm.Stage,
+		Next:         vhm.Next,
+	})
+	if err != nil {
+		logrus.Fatal("failed to marshal vHiveMetadata", err)
+	}
+	return d
+}
@@ -18,6 +18,8 @@ type Configuration struct {
 	TestMode bool
 
 	Functions []*common.Function
+	// Tenants the functions are assigned to in multi-tenant experiments
+	Tenants []TenantConfiguration
 }
 
 func (c *Configuration) WithWarmup() bool {
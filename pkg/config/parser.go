@@ -26,9 +26,12 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
+	"strings"
 
 	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 type FailureConfiguration struct {
@@ -39,6 +42,40 @@ type FailureConfiguration struct {
 	FailNode      string `json:"FailNode"`
 }
 
+// BurstEventConfiguration describes a surge of a fraction of functions at the same time, repeated every PeriodSeconds
+// if it is positive
+type BurstEventConfiguration struct {
+	StartSecond      float64 `json:"StartSecond"`
+	DurationSeconds  float64 `json:"DurationSeconds"`
+	PeriodSeconds    float64 `json:"PeriodSeconds"`
+	Amplitude        float64 `json:"Amplitude"`
+	FunctionFraction float64 `json:"FunctionFraction"`
+	ScaleCounts      bool    `json:"ScaleCounts"`
+}
+
+// TenantQuota limits the resources all functions of a tenant can request together, where zero means unlimited
+type TenantQuota struct {
+	CPUMilli  int `json:"CPUMilli"`
+	MemoryMiB int `json:"MemoryMiB"`
+	Pods      int `json:"Pods"`
+}
+
+// TenantConfiguration groups the functions of the owners into a tenant deployed in its own namespace
+type TenantConfiguration struct {
+	Name   string      `json:"Name"`
+	Owners []string    `json:"Owners"`
+	Quota  TenantQuota `json:"Quota"`
+}
+
+// ArrivalModelConfiguration selects how invocations of functions with a given trigger are placed in time
+type ArrivalModelConfiguration struct {
+	Model           string      `json:"Model"`
+	BatchSize       int         `json:"BatchSize"`
+	BatchIntervalMs float64     `json:"BatchIntervalMs"`
+	MMPPRates       []float64   `json:"MMPPRates"`
+	MMPPTransitions [][]float64 `json:"MMPPTransitions"`
+}
+
 type LoaderConfiguration struct {
 	Seed int64 `json:"Seed"`
 
@@ -50,6 +87,17 @@ type LoaderConfiguration struct {
 	DirigentControlPlaneIP   string `json:"DirigentControlPlaneIP"`
 	BusyLoopOnSandboxStartup bool   `json:"BusyLoopOnSandboxStartup"`
 
+	AppLevelDeployment  bool   `json:"AppLevelDeployment"`
+	AppFunctionSelector string `json:"AppFunctionSelector"`
+
+	MultiTenant        bool                  `json:"MultiTenant"`
+	Tenants            []TenantConfiguration `json:"Tenants"`
+	DefaultTenantQuota TenantQuota           `json:"DefaultTenantQuota"`
+
+	OpenWhiskAPIHost   string `json:"OpenWhiskAPIHost"`
+	OpenWhiskAuthKey   string `json:"OpenWhiskAuthKey"`
+	OpenWhiskNamespace string `json:"OpenWhiskNamespace"`
+
 	AsyncMode             bool   `json:"AsyncMode"`
 	AsyncResponseURL      string `json:"AsyncResponseURL"`
 	AsyncWaitToCollectMin int    `json:"AsyncWaitToCollectMin"`
@@ -64,15 +112,43 @@ type LoaderConfiguration struct {
 	RpsIterationMultiplier      int     `json:"RpsIterationMultiplier"`
 	RpsDataSizeMB               float64 `json:"RpsDataSizeMB"`
 	RpsFile                     string  `json:"RpsFile"`
-
-	TracePath          string `json:"TracePath"`
-	Granularity        string `json:"Granularity"`
-	OutputPathPrefix   string `json:"OutputPathPrefix"`
-	IATDistribution    string `json:"IATDistribution"`
-	CPULimit           string `json:"CPULimit"`
-	ExperimentDuration int    `json:"ExperimentDuration"`
-	WarmupDuration     int    `json:"WarmupDuration"`
-	PrepullMode        string `json:"PrepullMode"`
+	RpsProfile                  string  `json:"RpsProfile"`
+	RpsStart                    float64 `json:"RpsStart"`
+	RpsStepSize                 float64 `json:"RpsStepSize"`
+	RpsStepSeconds              int     `json:"RpsStepSeconds"`
+	RpsPeriodSeconds            int     `json:"RpsPeriodSeconds"`
+	RpsProfileFile              string  `json:"RpsProfileFile"`
+
+	TracePath          string  `json:"TracePath"`
+	TraceFormat        string  `json:"TraceFormat"`
+	Granularity        string  `json:"Granularity"`
+	OutputPathPrefix   string  `json:"OutputPathPrefix"`
+	IATDistribution    string  `json:"IATDistribution"`
+	IATShape           float64 `json:"IATShape"`
+	IATCDFPath         string  `json:"IATCDFPath"`
+	CPULimit           string  `json:"CPULimit"`
+	ExperimentDuration int     `json:"ExperimentDuration"`
+	WarmupDuration     int     `json:"WarmupDuration"`
+	PrepullMode        string  `json:"PrepullMode"`
+
+	RuntimeMemoryCorrelation float64 `json:"RuntimeMemoryCorrelation"`
+
+	StreamingSpecification bool                                 `json:"StreamingSpecification"`
+	SpecificationPath      string                               `json:"SpecificationPath"`
+	TriggerArrivalModels   map[string]ArrivalModelConfiguration `json:"TriggerArrivalModels"`
+	BurstEvents            []BurstEventConfiguration            `json:"BurstEvents"`
+
+	InvocationTraceFiles          []string          `json:"InvocationTraceFiles"`
+	DurationTraceFiles            []string          `json:"DurationTraceFiles"`
+	MemoryTraceFiles              []string          `json:"MemoryTraceFiles"`
+	TraceStatsMergePolicy         string            `json:"TraceStatsMergePolicy"`
+	TraceStartDay                 int               `json:"TraceStartDay"`
+	TraceStartMinute              int               `json:"TraceStartMinute"`
+	TraceColumnMapping            map[string]string `json:"TraceColumnMapping"`
+	SyntheticFunctionCount        int               `json:"SyntheticFunctionCount"`
+	SyntheticInvocationsPerMinute int               `json:"SyntheticInvocationsPerMinute"`
+	SyntheticRuntimeMs            int               `json:"SyntheticRuntimeMs"`
+	SyntheticMemoryMiB            int               `json:"SyntheticMemoryMiB"`
 
 	IsPartiallyPanic            bool   `json:"IsPartiallyPanic"`
 	EnableZipkinTracing         bool   `json:"EnableZipkinTracing"`
@@ -87,6 +163,13 @@ type LoaderConfiguration struct {
 	Width                        int  `json:"Width"`
 	Depth                        int  `json:"Depth"`
 	VSwarm                       bool `json:"VSwarm"`
+
+	WorkflowPath  string `json:"WorkflowPath"`
+	DAGFanIn      bool   `json:"DAGFanIn"`
+	DAGJoinPolicy string `json:"DAGJoinPolicy"`
+
+	DAGPayloadSize   *PayloadSizeConfiguration `json:"DAGPayloadSize"`
+	DAGOrchestration string                    `json:"DAGOrchestration"`
 }
 
 func ReadConfigurationFile(path string) LoaderConfiguration {
@@ -101,9 +184,24 @@ func ReadConfigurationFile(path string) LoaderConfiguration {
 		log.Fatal(err)
 	}
 
+	if err = validateTenants(config.Tenants); err != nil {
+		log.Fatal(err)
+	}
+
 	return config
 }
 
+// validateTenants checks that the names of the tenants can be used as the namespaces they are deployed in
+func validateTenants(tenants []TenantConfiguration) error {
+	for _, tenant := range tenants {
+		if errs := validation.IsDNS1123Label(tenant.Name); len(errs) > 0 {
+			return fmt.Errorf("invalid tenant name '%s': %s", tenant.Name, strings.Join(errs, "; "))
+		}
+	}
+
+	return nil
+}
+
 func ReadFailureConfiguration(path string) *FailureConfiguration {
 	byteValue, err := os.ReadFile(path)
 	if err != nil {
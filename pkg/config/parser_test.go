@@ -70,3 +70,44 @@ func TestConfigParser(t *testing.T) {
 		t.Error("Unexpected configuration read.")
 	}
 }
+
+func TestWorkflowParser(t *testing.T) {
+	workflows := ReadWorkflowConfiguration("test_workflow.yaml")
+
+	if len(workflows) != 1 {
+		t.Fatalf("Expected a single workflow, got %d.", len(workflows))
+	}
+
+	workflow := workflows[0]
+	if workflow.Name != "video-pipeline" ||
+		len(workflow.Invocations) != 3 || workflow.Invocations[0] != 2 ||
+		len(workflow.Stages) != 4 ||
+		workflow.Stages[1].Function != "detect-function" || workflow.Stages[1].RuntimeMs != 250 ||
+		workflow.Stages[2].MemoryMiB != 512 ||
+		len(workflow.Edges) != 2 || len(workflow.Edges[0].To) != 2 || workflow.Edges[1].To[0] != "encode" {
+
+		t.Errorf("Unexpected workflow definition %+v.", workflow)
+	}
+}
+
+func TestValidateTenants(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{name: "tenant-0", valid: true},
+		{name: "", valid: false},
+		{name: "Tenant", valid: false},
+		{name: "tenant_a", valid: false},
+		{name: "tenant-", valid: false},
+		{name: "ns; rm -rf /", valid: false},
+		{name: strings.Repeat("a", 64), valid: false},
+	}
+
+	for _, test := range tests {
+		err := validateTenants([]TenantConfiguration{{Name: "tenant-a"}, {Name: test.name}})
+		if (err == nil) != test.valid {
+			t.Errorf("Unexpected validation of tenant name '%s' - %v.", test.name, err)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// WorkflowStageConfiguration maps a stage of a workflow onto a function of the trace, identified by its name or its
+// HashFunction, where positive RuntimeMs and MemoryMiB override the ones sampled from the trace
+type WorkflowStageConfiguration struct {
+	Name      string `json:"Name" yaml:"Name"`
+	Function  string `json:"Function" yaml:"Function"`
+	RuntimeMs int    `json:"RuntimeMs" yaml:"RuntimeMs"`
+	MemoryMiB int    `json:"MemoryMiB" yaml:"MemoryMiB"`
+}
+
+// PayloadSizeConfiguration is the distribution of the size of the data passed along the edges of a DAG, which is
+// either constant, uniform between MinBytes and MaxBytes, exponential or lognormal with the given mean and Sigma as
+// the standard deviation of its logarithm. Positive MinBytes and MaxBytes also bound the other distributions.
+type PayloadSizeConfiguration struct {
+	Distribution string  `json:"Distribution" yaml:"Distribution"`
+	MeanBytes    float64 `json:"MeanBytes" yaml:"MeanBytes"`
+	MinBytes     float64 `json:"MinBytes" yaml:"MinBytes"`
+	MaxBytes     float64 `json:"MaxBytes" yaml:"MaxBytes"`
+	Sigma        float64 `json:"Sigma" yaml:"Sigma"`
+}
+
+// WorkflowEdgeConfiguration invokes the stages in To once the stage From has completed, in parallel if there are
+// multiple of them, passing them data of the given size
+type WorkflowEdgeConfiguration struct {
+	From    string                    `json:"From" yaml:"From"`
+	To      []string                  `json:"To" yaml:"To"`
+	Payload *PayloadSizeConfiguration `json:"Payload" yaml:"Payload"`
+}
+
+// WorkflowConfiguration describes a workflow of stages connected by edges, which is invoked Invocations[i] times in
+// time unit i of the experiment, or as often as its entry function in the trace if Invocations is empty
+type WorkflowConfiguration struct {
+	Name        string                       `json:"Name" yaml:"Name"`
+	Entry       string                       `json:"Entry" yaml:"Entry"`
+	Invocations []int                        `json:"Invocations" yaml:"Invocations"`
+	Stages      []WorkflowStageConfiguration `json:"Stages" yaml:"Stages"`
+	Edges       []WorkflowEdgeConfiguration  `json:"Edges" yaml:"Edges"`
+}
+
+// ReadWorkflowConfiguration reads workflow definitions from a JSON file, or from a YAML file if the extension is .yaml
+// or .yml
+func ReadWorkflowConfiguration(path string) []WorkflowConfiguration {
+	byteValue, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var workflows []WorkflowConfiguration
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(byteValue, &workflows)
+	default:
+		err = json.Unmarshal(byteValue, &workflows)
+	}
+	if err != nil {
+		log.Fatalf("Failed to parse workflow definitions %s - %v", path, err)
+	}
+
+	return workflows
+}
@@ -75,6 +75,11 @@ func (d *Driver) writeAsyncRecordsToLog(logCh chan *metric.ExecutionRecord) {
 				record.ResponseTime += int64(e2e)
 				record.ResponseTime += timeToFetchResponse
 
+				// asynchronous invocations only count towards their tenant once their outcome is known
+				if d.tenantStatistics != nil {
+					d.tenantStatistics.add(record.Tenant, record)
+				}
+
 				logCh <- record
 			}()
 		}
@@ -1,15 +1,26 @@
 package clients
 
 import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
 	log "github.com/sirupsen/logrus"
 	"github.com/vhive-serverless/loader/pkg/common"
 	mc "github.com/vhive-serverless/loader/pkg/metric"
-	"io"
-	"sync"
 )
 
+type HTTPResBody struct {
+	DurationInMicroSec uint32 `json:"DurationInMicroSec"`
+	MemoryUsageInKb    uint32 `json:"MemoryUsageInKb"`
+}
+
 type awsLambdaInvoker struct {
 	announceDoneExe *sync.WaitGroup
 }
@@ -56,3 +67,95 @@ func (i *awsLambdaInvoker) Invoke(function *common.Function, runtimeSpec *common
 
 	return true, record
 }
+
+func httpInvocation(dataString string, function *common.Function, AnnounceDoneExe *sync.WaitGroup, tlsSkipVerify bool) (bool, *mc.ExecutionRecordBase, *http.Response) {
+	defer AnnounceDoneExe.Done()
+
+	record := &mc.ExecutionRecordBase{}
+
+	start := time.Now()
+	record.StartTime = start.UnixMicro()
+	record.Instance = function.Name
+	requestURL := function.Endpoint
+	if tlsSkipVerify {
+		http.DefaultTransport.(*http.Transport).TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+
+	if dataString != "" {
+		requestURL += "?" + dataString
+	}
+	req, err := http.NewRequest(http.MethodGet, requestURL, bytes.NewBuffer([]byte("")))
+	if err != nil {
+		log.Warnf("http request creation failed for function %s - %s", function.Name, err)
+
+		record.ResponseTime = time.Since(start).Microseconds()
+		record.ConnectionTimeout = true
+
+		return false, record, nil
+	}
+
+	req.Header.Set("Content-Type", "application/json") // To avoid data being base64encoded
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		log.Debugf("http request for function %s failed - %s", function.Name, err)
+
+		record.ResponseTime = time.Since(start).Microseconds()
+		record.ConnectionTimeout = true
+
+		return false, record, resp
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		log.Debugf("http request for function %s failed - error code: %s", function.Name, resp.Status)
+
+		record.ResponseTime = time.Since(start).Microseconds()
+		record.ConnectionTimeout = true
+
+		return false, record, resp
+	}
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		log.Warnf("Failed to read output %s - %v", function.Name, err)
+
+		record.ResponseTime = time.Since(start).Microseconds()
+		record.FunctionTimeout = true
+
+		return false, record, resp
+	}
+
+	rawJson, err := base64.StdEncoding.DecodeString(string(bodyBytes))
+	if err != nil {
+		log.Warnf("Failed to decode base64 output %s - %v", function.Name, err)
+
+		record.ResponseTime = time.Since(start).Microseconds()
+		record.FunctionTimeout = true
+
+		return false, record, resp
+	}
+
+	var deserializedResponse FunctionResponse
+	err = json.Unmarshal(rawJson, &deserializedResponse)
+	if err != nil {
+		log.Warnf("Failed to deserialize response %s - %v", function.Name, err)
+
+		record.ResponseTime = time.Since(start).Microseconds()
+		record.FunctionTimeout = true
+
+		return false, record, resp
+	}
+
+	record.Instance = deserializedResponse.Function
+	record.ResponseTime = time.Since(start).Microseconds()
+	record.ActualDuration = uint32(deserializedResponse.ExecutionTime)
+
+	return true, record, resp
+}
+
+func logInvocationSummary(function *common.Function, record *mc.ExecutionRecordBase, res *http.Response) {
+	log.Tracef("(Replied)\t %s: %d[ms]", function.Name, record.ActualDuration)
+	log.Tracef("(E2E Latency) %s: %.2f[ms]\n", function.Name, float64(record.ResponseTime)/1e3)
+	log.Tracef("(Client status code) %s: %d", function.Name, res.StatusCode)
+}
@@ -26,6 +26,7 @@ package clients
 
 import (
 	"context"
+	"encoding/json"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"github.com/vhive-serverless/loader/pkg/common"
@@ -34,7 +35,10 @@ import (
 	helloworld "github.com/vhive-serverless/vSwarm/utils/protobuf/helloworld"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"strings"
 	"time"
 
@@ -42,26 +46,32 @@ import (
 )
 
 type invoker interface {
-	Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification, conn *grpc.ClientConn, record *mc.ExecutionRecord, executionCxt context.Context) bool
+	Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification, conn *grpc.ClientConn, record *mc.ExecutionRecord, executionCxt context.Context, opts ...grpc.CallOption) bool
 }
 
 type ExecutorRPC struct {
 }
 
-func (i ExecutorRPC) Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification, conn *grpc.ClientConn, record *mc.ExecutionRecord, executionCxt context.Context) bool {
+func (i ExecutorRPC) Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification, conn *grpc.ClientConn, record *mc.ExecutionRecord, executionCxt context.Context, opts ...grpc.CallOption) bool {
 	grpcClient := proto.NewExecutorClient(conn)
 
+	message := "nothing"
+	if runtimeSpec.PayloadBytes > 0 {
+		message = DAGPayload(runtimeSpec.PayloadBytes)
+	}
+
 	response, err := grpcClient.Execute(executionCxt, &proto.FaasRequest{
-		Message:           "nothing",
+		Message:           message,
 		RuntimeInMilliSec: uint32(runtimeSpec.Runtime),
 		MemoryInMebiBytes: uint32(runtimeSpec.Memory),
-	})
+	}, opts...)
 
 	if err != nil {
 		logrus.Debugf("gRPC timeout exceeded for function %s - %s", function.Name, err)
 
 		record.ConnectionTimeout = true // WithBlock deprecated in new gRPC interface
 		record.FunctionTimeout = true
+		record.Throttled = isThrottledError(err)
 
 		return false
 	}
@@ -84,20 +94,21 @@ func (i ExecutorRPC) Invoke(function *common.Function, runtimeSpec *common.Runti
 type SayHelloRPC struct {
 }
 
-func (i SayHelloRPC) Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification, conn *grpc.ClientConn, record *mc.ExecutionRecord, executionCxt context.Context) bool {
+func (i SayHelloRPC) Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification, conn *grpc.ClientConn, record *mc.ExecutionRecord, executionCxt context.Context, opts ...grpc.CallOption) bool {
 	grpcClient := helloworld.NewGreeterClient(conn)
 	response, err := grpcClient.SayHello(executionCxt, &helloworld.HelloRequest{
 		Name: "Invoke Relay",
-		VHiveMetadata: MakeVHiveMetadata(
+		VHiveMetadata: common.MakeVHiveMetadata(
 			uuid.New().String(),
 			uuid.New().String(),
 			time.Now().UTC(),
 		),
-	})
+	}, opts...)
 	if err != nil {
 		logrus.Debugf("gRPC timeout exceeded for function %s - %s", function.Name, err)
 		record.ConnectionTimeout = true
 		record.FunctionTimeout = true
+		record.Throttled = isThrottledError(err)
 
 		return false
 	}
@@ -121,6 +132,57 @@ func newGRPCInvoker(cfg *config.LoaderConfiguration, invoker invoker) *grpcInvok
 }
 
 func (i *grpcInvoker) Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification) (bool, *mc.ExecutionRecord) {
+	return i.invoke(function, runtimeSpec, nil)
+}
+
+// InvokeChain invokes the root of a DAG, whose function invokes the next stages given in the vHive metadata itself,
+// and returns the records of the stages invoked after it
+func (i *grpcInvoker) InvokeChain(function *common.Function, runtimeSpec *common.RuntimeSpecification, vhm common.VHiveMetadata) (bool, *mc.ExecutionRecord, []*mc.ExecutionRecord) {
+	var trailer metadata.MD
+	success, record := i.invoke(function, runtimeSpec,
+		common.MakeChainedVHiveMetadata(vhm.WorkflowId, vhm.InvocationId, vhm.InvokedOn, vhm.Stage, vhm.Next),
+		grpc.Trailer(&trailer))
+
+	var hops []common.ChainHop
+	if values := trailer.Get(common.ChainHopsKey); len(values) > 0 {
+		if err := json.Unmarshal([]byte(values[0]), &hops); err != nil {
+			logrus.Warnf("Failed to unmarshal the records of chained stages of %s - %v", function.Name, err)
+		}
+	}
+
+	records := make([]*mc.ExecutionRecord, len(hops))
+	for j, hop := range hops {
+		records[j] = chainHopRecord(hop)
+	}
+
+	return success, record, records
+}
+
+func chainHopRecord(hop common.ChainHop) *mc.ExecutionRecord {
+	record := &mc.ExecutionRecord{
+		ExecutionRecordBase: mc.ExecutionRecordBase{
+			Instance:          extractInstanceName(hop.Message),
+			StartTime:         hop.StartTime,
+			ResponseTime:      hop.ResponseTime,
+			ActualDuration:    hop.DurationInMicroSec,
+			ConnectionTimeout: !hop.Success,
+			FunctionTimeout:   !hop.Success,
+		},
+		Stage:        hop.Stage,
+		ParentStage:  hop.ParentStage,
+		PayloadBytes: hop.PayloadBytes,
+	}
+
+	if strings.HasPrefix(hop.Message, "FAILURE - mem_alloc") {
+		record.MemoryAllocationTimeout = true
+	} else {
+		record.ActualMemoryUsage = common.Kib2Mib(hop.MemoryUsageInKb)
+	}
+
+	return record
+}
+
+func (i *grpcInvoker) invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification, vHiveMetadata []byte, opts ...grpc.CallOption) (bool, *mc.ExecutionRecord) {
 	logrus.Tracef("(Invoke)\t %s: %d[ms], %d[MiB]", function.Name, runtimeSpec.Runtime, runtimeSpec.Memory)
 
 	record := &mc.ExecutionRecord{
@@ -138,11 +200,17 @@ func (i *grpcInvoker) Invoke(function *common.Function, runtimeSpec *common.Runt
 	var dialOptions []grpc.DialOption
 	dialOptions = append(dialOptions, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if strings.Contains(strings.ToLower(i.cfg.Platform), "dirigent") {
-		dialOptions = append(dialOptions, grpc.WithAuthority(function.Name)) // Dirigent specific
+		dialOptions = append(dialOptions, grpc.WithAuthority(function.ServiceName())) // Dirigent specific
 	}
 	if i.cfg.EnableZipkinTracing {
 		dialOptions = append(dialOptions, grpc.WithStatsHandler(otelgrpc.NewClientHandler()))
 	}
+	var payloadStats *payloadStatsHandler
+	if runtimeSpec.PayloadBytes > 0 {
+		payloadStats = &payloadStatsHandler{start: start}
+		dialOptions = append(dialOptions, grpc.WithStatsHandler(payloadStats))
+		record.PayloadBytes = runtimeSpec.PayloadBytes
+	}
 
 	grpcStart := time.Now()
 
@@ -160,12 +228,40 @@ func (i *grpcInvoker) Invoke(function *common.Function, runtimeSpec *common.Runt
 	record.GRPCConnectionEstablishTime = time.Since(grpcStart).Microseconds()
 	executionCxt, cancelExecution := context.WithTimeout(context.Background(), time.Duration(i.cfg.GRPCFunctionTimeoutSeconds)*time.Second)
 	defer cancelExecution()
-	success := i.invoker.Invoke(function, runtimeSpec, conn, record, executionCxt)
+	if function.AppService != "" {
+		// the service of the app selects the function by the metadata header
+		executionCxt = metadata.AppendToOutgoingContext(executionCxt, "function", function.Name)
+	}
+	if vHiveMetadata != nil {
+		executionCxt = metadata.AppendToOutgoingContext(executionCxt, common.VHiveMetadataKey, string(vHiveMetadata))
+	}
+	success := i.invoker.Invoke(function, runtimeSpec, conn, record, executionCxt, opts...)
 	record.ResponseTime = time.Since(start).Microseconds()
+	if payloadStats != nil {
+		record.TransferTime = payloadStats.transferTime.Load()
+	}
 	logrus.Tracef("(E2E Latency) %s: %.2f[ms]\n", function.Name, float64(record.ResponseTime)/1e3)
 	return success, record
 }
 
+// isThrottledError reports whether the invocation was rejected for lack of capacity. Proxies in front of functions,
+// e.g., the Knative activator, reply with HTTP 429 or 503, which gRPC reports as unavailability.
+func isThrottledError(err error) bool {
+	s, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+
+	switch s.Code() {
+	case codes.ResourceExhausted:
+		return true
+	case codes.Unavailable:
+		return strings.Contains(s.Message(), "429") || strings.Contains(s.Message(), "503")
+	default:
+		return false
+	}
+}
+
 func extractInstanceName(data string) string {
 	indexOfHyphen := strings.LastIndex(data, common.FunctionNamePrefix)
 	if indexOfHyphen == -1 {
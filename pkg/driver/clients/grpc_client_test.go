@@ -157,6 +157,30 @@ func TestGRPCClientWithServerReachable(t *testing.T) {
 	}
 }
 
+func TestGRPCClientWithPayload(t *testing.T) {
+	address, port := "localhost", 18083
+	function := testFunction
+	function.Endpoint = fmt.Sprintf("%s:%d", address, port)
+
+	go standard.StartGRPCServer(address, port, standard.TraceFunction, "")
+	time.Sleep(2 * time.Second)
+
+	runtimeSpecs := testRuntimeSpecs
+	runtimeSpecs.PayloadBytes = 1 << 20
+	PrepareDAGPayload(runtimeSpecs.PayloadBytes, 42)
+
+	invoker := CreateInvoker(createFakeLoaderConfiguration(), nil, nil)
+	success, record := invoker.Invoke(&function, &runtimeSpecs)
+
+	if !success ||
+		record.PayloadBytes != runtimeSpecs.PayloadBytes ||
+		record.TransferTime <= 0 ||
+		record.TransferTime > record.ResponseTime {
+
+		t.Errorf("Unexpected payload transfer of %d bytes in %d μs.", record.PayloadBytes, record.TransferTime)
+	}
+}
+
 func TestVSwarmClientWithServerReachable(t *testing.T) {
 	address, port := "localhost", 18081
 	testFunction.Endpoint = fmt.Sprintf("%s:%d", address, port)
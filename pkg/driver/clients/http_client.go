@@ -7,9 +7,11 @@ import (
 	"io"
 	"mime/multipart"
 	"net/http"
+	"net/http/httptrace"
 	"os"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -106,7 +108,7 @@ func (i *httpInvoker) Invoke(function *common.Function, runtimeSpec *common.Runt
 	if body := composeBusyLoopBody(function.Name, function.DirigentMetadata.Image, runtimeSpec.Runtime, function.DirigentMetadata.IterationMultiplier); isDandelion && body != nil {
 		requestBody = body
 	}
-	if i.cfg.RpsTarget != 0 {
+	if i.cfg.TracePath == "RPS" {
 		ts := time.Now()
 		if i.cfg.RpsFile != "" {
 			requestBody = CreateFilePayload(i.cfg.RpsFile)
@@ -116,6 +118,10 @@ func (i *httpInvoker) Invoke(function *common.Function, runtimeSpec *common.Runt
 			log.Debugf("Took %v to generate request body.", time.Since(ts))
 		}
 	}
+	if runtimeSpec.PayloadBytes > 0 && !isDandelion {
+		requestBody = bytes.NewBufferString(DAGPayload(runtimeSpec.PayloadBytes))
+		record.PayloadBytes = runtimeSpec.PayloadBytes
+	}
 
 	start := time.Now()
 	record.StartTime = start.UnixMicro()
@@ -133,7 +139,7 @@ func (i *httpInvoker) Invoke(function *common.Function, runtimeSpec *common.Runt
 
 	// add system specific stuff
 	if !isKnative {
-		req.Host = function.Name
+		req.Host = function.ServiceName()
 	}
 
 	req.Header.Set("workload", function.DirigentMetadata.Image)
@@ -143,8 +149,19 @@ func (i *httpInvoker) Invoke(function *common.Function, runtimeSpec *common.Runt
 	req.Header.Set("multiplier", strconv.Itoa(function.DirigentMetadata.IterationMultiplier))
 	req.Header.Set("io_percentage", strconv.Itoa(function.DirigentMetadata.IOPercentage))
 
+	var transferTime atomic.Int64
+	if record.PayloadBytes > 0 {
+		req = req.WithContext(httptrace.WithClientTrace(req.Context(), &httptrace.ClientTrace{
+			WroteRequest: func(httptrace.WroteRequestInfo) {
+				transferTime.Store(time.Since(start).Microseconds())
+			},
+		}))
+	}
+
 	if isDandelion {
 		req.URL.Path = "/hot/matmul"
+	} else if function.AppService != "" && i.cfg.AppFunctionSelector == "path" {
+		req.URL.Path = "/" + function.Name
 	}
 
 	resp, err := i.client.Do(req)
@@ -158,6 +175,7 @@ func (i *httpInvoker) Invoke(function *common.Function, runtimeSpec *common.Runt
 	}
 
 	record.GRPCConnectionEstablishTime = time.Since(start).Microseconds()
+	record.TransferTime = transferTime.Load()
 
 	defer HandleBodyClosing(resp)
 	body, err := io.ReadAll(resp.Body)
@@ -173,6 +191,7 @@ func (i *httpInvoker) Invoke(function *common.Function, runtimeSpec *common.Runt
 
 		record.ResponseTime = time.Since(start).Microseconds()
 		record.FunctionTimeout = true
+		record.Throttled = resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable
 
 		return false, record
 	}
@@ -13,7 +13,12 @@ type Invoker interface {
 	Invoke(*common.Function, *common.RuntimeSpecification) (bool, *metric.ExecutionRecord)
 }
 
-func CreateInvoker(cfg *config.LoaderConfiguration, announceDoneExe *sync.WaitGroup, readOpenWhiskMetadata *sync.Mutex) Invoker {
+// ChainInvoker invokes the root of a DAG whose functions invoke the next stages themselves, i.e., server-side chaining
+type ChainInvoker interface {
+	InvokeChain(*common.Function, *common.RuntimeSpecification, common.VHiveMetadata) (bool, *metric.ExecutionRecord, []*metric.ExecutionRecord)
+}
+
+func CreateInvoker(cfg *config.LoaderConfiguration, announceDoneExe *sync.WaitGroup, openWhiskInvocations *common.LockFreeQueue[*OpenWhiskInvocation]) Invoker {
 	switch cfg.Platform {
 	case "AWSLambda":
 		return newAWSLambdaInvoker(announceDoneExe)
@@ -36,7 +41,7 @@ func CreateInvoker(cfg *config.LoaderConfiguration, announceDoneExe *sync.WaitGr
 			return newHTTPInvoker(cfg)
 		}
 	case "OpenWhisk":
-		return newOpenWhiskInvoker(announceDoneExe, readOpenWhiskMetadata)
+		return newOpenWhiskInvoker(cfg, openWhiskInvocations)
 	default:
 		logrus.Fatal("Unsupported platform.")
 	}
@@ -0,0 +1,249 @@
+package clients
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/vhive-serverless/loader/pkg/config"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+const openWhiskDefaultNamespace = "_"
+
+// OpenWhiskClient talks to the OpenWhisk REST API directly, replacing the calls to the `wsk` CLI.
+// API reference: https://github.com/apache/openwhisk/blob/master/docs/rest_api.md
+type OpenWhiskClient struct {
+	apiHost   string
+	namespace string
+	user      string
+	password  string
+
+	client *http.Client
+}
+
+type openWhiskKeyValue struct {
+	Key   string      `json:"key"`
+	Value interface{} `json:"value"`
+}
+
+type openWhiskAction struct {
+	Namespace   string              `json:"namespace,omitempty"`
+	Name        string              `json:"name,omitempty"`
+	Exec        openWhiskActionExec `json:"exec"`
+	Annotations []openWhiskKeyValue `json:"annotations,omitempty"`
+}
+
+type openWhiskActionExec struct {
+	Kind string `json:"kind"`
+	Code string `json:"code"`
+}
+
+type openWhiskActivationResponse struct {
+	Status     string          `json:"status"`
+	StatusCode int             `json:"statusCode"`
+	Success    bool            `json:"success"`
+	Result     json.RawMessage `json:"result"`
+}
+
+// OpenWhiskActivation is the subset of the activation record returned by OpenWhisk that the loader uses
+type OpenWhiskActivation struct {
+	ActivationID string                      `json:"activationId"`
+	Start        int64                       `json:"start"`    // ms since epoch
+	End          int64                       `json:"end"`      // ms since epoch
+	Duration     int64                       `json:"duration"` // ms
+	Annotations  []openWhiskKeyValue         `json:"annotations"`
+	Response     openWhiskActivationResponse `json:"response"`
+}
+
+func NewOpenWhiskClient(cfg *config.LoaderConfiguration) *OpenWhiskClient {
+	namespace := cfg.OpenWhiskNamespace
+	if namespace == "" {
+		namespace = openWhiskDefaultNamespace
+	}
+
+	apiHost := strings.TrimSuffix(cfg.OpenWhiskAPIHost, "/")
+	if apiHost != "" && !strings.HasPrefix(apiHost, "http://") && !strings.HasPrefix(apiHost, "https://") {
+		apiHost = "https://" + apiHost
+	}
+
+	// auth key has the format <uuid>:<key>
+	user, password, _ := strings.Cut(cfg.OpenWhiskAuthKey, ":")
+
+	return &OpenWhiskClient{
+		apiHost:   apiHost,
+		namespace: namespace,
+		user:      user,
+		password:  password,
+
+		client: &http.Client{
+			Timeout: time.Duration(cfg.GRPCFunctionTimeoutSeconds) * time.Second,
+			Transport: &http.Transport{
+				// OpenWhisk deployments typically use self-signed certificates (same as `wsk -i`)
+				TLSClientConfig:     &tls.Config{InsecureSkipVerify: true},
+				MaxIdleConns:        100,
+				MaxIdleConnsPerHost: 100,
+				IdleConnTimeout:     5 * time.Second,
+			},
+		},
+	}
+}
+
+// ActionURL returns the REST endpoint of the action with the given name
+func (c *OpenWhiskClient) ActionURL(name string) string {
+	return fmt.Sprintf("%s/api/v1/namespaces/%s/actions/%s", c.apiHost, url.PathEscape(c.namespace), url.PathEscape(name))
+}
+
+func (c *OpenWhiskClient) activationURL(activationID string) string {
+	return fmt.Sprintf("%s/api/v1/namespaces/%s/activations/%s", c.apiHost, url.PathEscape(c.namespace), url.PathEscape(activationID))
+}
+
+func (c *OpenWhiskClient) do(method string, requestURL string, body interface{}) (*http.Response, []byte, error) {
+	var requestBody io.Reader = http.NoBody
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		requestBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, requestURL, requestBody)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req.SetBasicAuth(c.user, c.password)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer HandleBodyClosing(resp)
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp, nil, err
+	}
+
+	return resp, responseBody, nil
+}
+
+// CreateAction creates or overwrites a web action with the given source code
+func (c *OpenWhiskClient) CreateAction(name string, kind string, code string) error {
+	action := openWhiskAction{
+		Namespace: c.namespace,
+		Name:      name,
+		Exec: openWhiskActionExec{
+			Kind: kind,
+			Code: code,
+		},
+		Annotations: []openWhiskKeyValue{
+			{Key: "web-export", Value: true},
+			{Key: "raw-http", Value: false},
+			{Key: "final", Value: true},
+		},
+	}
+
+	resp, body, err := c.do(http.MethodPut, c.ActionURL(name)+"?overwrite=true", action)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status code %d - %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+func (c *OpenWhiskClient) DeleteAction(name string) error {
+	resp, body, err := c.do(http.MethodDelete, c.ActionURL(name), nil)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status code %d - %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+// InvokeAction performs a blocking invocation of the action behind the given endpoint. OpenWhisk replies with
+// 200 and the full activation record if the action completed within the blocking window, or with 202 and only the
+// activation ID otherwise.
+func (c *OpenWhiskClient) InvokeAction(endpoint string, parameters map[string]string) (int, *OpenWhiskActivation, error) {
+	resp, body, err := c.do(http.MethodPost, endpoint+"?blocking=true", parameters)
+	if err != nil {
+		if resp != nil {
+			return resp.StatusCode, nil, err
+		}
+
+		return 0, nil, err
+	}
+
+	var activation OpenWhiskActivation
+	if len(body) > 0 {
+		if err = json.Unmarshal(body, &activation); err != nil {
+			return resp.StatusCode, nil, err
+		}
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		return resp.StatusCode, &activation, fmt.Errorf("status code %d - %s", resp.StatusCode, string(body))
+	}
+
+	return resp.StatusCode, &activation, nil
+}
+
+func (c *OpenWhiskClient) GetActivation(activationID string) (*OpenWhiskActivation, error) {
+	resp, body, err := c.do(http.MethodGet, c.activationURL(activationID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status code %d - %s", resp.StatusCode, string(body))
+	}
+
+	var activation OpenWhiskActivation
+	if err = json.Unmarshal(body, &activation); err != nil {
+		return nil, err
+	}
+
+	return &activation, nil
+}
+
+// Metadata extracts wait time, initialization time and start type from the activation annotations
+func (a *OpenWhiskActivation) Metadata() ActivationMetadata {
+	result := ActivationMetadata{
+		Duration:  uint32(a.Duration),
+		StartType: mc.Hot,
+	}
+
+	for _, annotation := range a.Annotations {
+		value, ok := annotation.Value.(float64)
+		if !ok {
+			continue
+		}
+
+		switch annotation.Key {
+		case "waitTime":
+			result.WaitTime = int64(value)
+		case "initTime":
+			result.StartType = mc.Cold
+			result.InitTime = int64(value)
+		}
+	}
+
+	return result
+}
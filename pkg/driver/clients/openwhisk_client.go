@@ -1,20 +1,14 @@
 package clients
 
 import (
-	"bytes"
-	"crypto/tls"
-	"encoding/base64"
 	"encoding/json"
-	"fmt"
-	"io"
 	"net/http"
-	"os/exec"
-	"strings"
-	"sync"
+	"strconv"
 	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
 	mc "github.com/vhive-serverless/loader/pkg/metric"
 )
 
@@ -25,184 +19,104 @@ type ActivationMetadata struct {
 	InitTime  int64 //ms
 }
 
-type HTTPResBody struct {
-	DurationInMicroSec uint32 `json:"DurationInMicroSec"`
-	MemoryUsageInKb    uint32 `json:"MemoryUsageInKb"`
+// OpenWhiskInvocation links an execution record to its OpenWhisk activation, whose metadata is fetched only after
+// the experiment finishes not to load the OpenWhisk controller during the experiment (Issue 329:
+// https://github.com/vhive-serverless/invitro/issues/329)
+type OpenWhiskInvocation struct {
+	Record         *mc.ExecutionRecord
+	ActivationID   string
+	HttpStatusCode int
 }
 
 type openWhiskInvoker struct {
-	announceDoneExe       *sync.WaitGroup
-	readOpenWhiskMetadata *sync.Mutex
+	client      *OpenWhiskClient
+	invocations *common.LockFreeQueue[*OpenWhiskInvocation]
 }
 
-func newOpenWhiskInvoker(announceDoneExe *sync.WaitGroup, readOpenWhiskMetadata *sync.Mutex) *openWhiskInvoker {
+func newOpenWhiskInvoker(cfg *config.LoaderConfiguration, invocations *common.LockFreeQueue[*OpenWhiskInvocation]) *openWhiskInvoker {
 	return &openWhiskInvoker{
-		announceDoneExe:       announceDoneExe,
-		readOpenWhiskMetadata: readOpenWhiskMetadata,
+		client:      NewOpenWhiskClient(cfg),
+		invocations: invocations,
 	}
 }
 
 func (i *openWhiskInvoker) Invoke(function *common.Function, runtimeSpec *common.RuntimeSpecification) (bool, *mc.ExecutionRecord) {
 	log.Tracef("(Invoke)\t %s: %d[ms], %d[MiB]", function.Name, runtimeSpec.Runtime, runtimeSpec.Memory)
 
-	qs := fmt.Sprintf("cpu=%d", runtimeSpec.Runtime)
-
-	success, executionRecordBase, res := httpInvocation(qs, function, i.announceDoneExe, true)
-	i.announceDoneExe.Wait() // To postpone querying OpenWhisk during the experiment for performance reasons (Issue 329: https://github.com/vhive-serverless/invitro/issues/329)
-
-	executionRecordBase.RequestedDuration = uint32(runtimeSpec.Runtime * 1e3)
-	record := &mc.ExecutionRecord{ExecutionRecordBase: *executionRecordBase}
-	if !success {
-		return false, record
-	}
-
-	activationID := res.Header.Get("X-Openwhisk-Activation-Id")
-	i.readOpenWhiskMetadata.Lock()
-
-	//read data from OpenWhisk based on the activation ID
-	cmd := exec.Command("wsk", "-i", "activation", "get", activationID)
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	err := cmd.Run()
-	if err != nil {
-		log.Debugf("error reading activation information from OpenWhisk %s - %s", function.Name, err)
-		i.readOpenWhiskMetadata.Unlock()
-		return false, record
-	}
-
-	i.readOpenWhiskMetadata.Unlock()
-	err, activationMetadata := parseActivationMetadata(out.String())
-	if err != nil {
-		log.Debugf("error parsing activation metadata %s - %s", function.Name, err)
-		return false, record
-	}
-
-	record.ActualDuration = activationMetadata.Duration * 1000 //ms to micro sec
-	/*record.StartType = activationMetadata.StartType
-	record.InitTime = activationMetadata.InitTime * 1000 //ms to micro sec
-	record.WaitTime = activationMetadata.WaitTime * 1000 //ms to micro sec*/
-
-	logInvocationSummary(function, &record.ExecutionRecordBase, res)
-
-	return true, record
-}
-
-func parseActivationMetadata(response string) (error, ActivationMetadata) {
-	var result ActivationMetadata
-	var jsonMap map[string]interface{}
-
-	ind := strings.Index(response, "{")
-	err := json.Unmarshal([]byte(response[ind:]), &jsonMap)
-	if err != nil {
-		return err, result
+	record := &mc.ExecutionRecord{
+		ExecutionRecordBase: mc.ExecutionRecordBase{
+			Instance:          function.Name,
+			RequestedDuration: uint32(runtimeSpec.Runtime * 1e3),
+		},
 	}
 
-	result.Duration = uint32(jsonMap["duration"].(float64))
-	result.StartType = mc.Hot
-	result.InitTime = 0
-	annotations := jsonMap["annotations"].([]interface{})
-	for i := 0; i < len(annotations); i++ {
-		annotation := annotations[i].(map[string]interface{})
-
-		if annotation["key"] == "waitTime" {
-			result.WaitTime = int64(annotation["value"].(float64))
-		} else if annotation["key"] == "initTime" {
-			result.StartType = mc.Cold
-			result.InitTime = int64(annotation["value"].(float64))
-		}
-	}
-
-	return nil, result
-}
-
-func httpInvocation(dataString string, function *common.Function, AnnounceDoneExe *sync.WaitGroup, tlsSkipVerify bool) (bool, *mc.ExecutionRecordBase, *http.Response) {
-	defer AnnounceDoneExe.Done()
-
-	record := &mc.ExecutionRecordBase{}
-
 	start := time.Now()
 	record.StartTime = start.UnixMicro()
-	record.Instance = function.Name
-	requestURL := function.Endpoint
-	if tlsSkipVerify {
-		http.DefaultTransport.(*http.Transport).TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-	}
-
-	if dataString != "" {
-		requestURL += "?" + dataString
-	}
-	req, err := http.NewRequest(http.MethodGet, requestURL, bytes.NewBuffer([]byte("")))
-	if err != nil {
-		log.Warnf("http request creation failed for function %s - %s", function.Name, err)
 
-		record.ResponseTime = time.Since(start).Microseconds()
-		record.ConnectionTimeout = true
+	statusCode, activation, err := i.client.InvokeAction(function.Endpoint, map[string]string{
+		"cpu": strconv.Itoa(runtimeSpec.Runtime),
+	})
+	record.ResponseTime = time.Since(start).Microseconds()
 
-		return false, record, nil
+	if activation != nil && activation.ActivationID != "" && i.invocations != nil {
+		i.invocations.Enqueue(&OpenWhiskInvocation{
+			Record:         record,
+			ActivationID:   activation.ActivationID,
+			HttpStatusCode: statusCode,
+		})
 	}
 
-	req.Header.Set("Content-Type", "application/json") // To avoid data being base64encoded
-
-	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		log.Debugf("http request for function %s failed - %s", function.Name, err)
+		log.Debugf("OpenWhisk invocation of %s failed - %s", function.Name, err)
 
-		record.ResponseTime = time.Since(start).Microseconds()
-		record.ConnectionTimeout = true
+		if statusCode == 0 {
+			record.ConnectionTimeout = true
+		} else {
+			record.FunctionTimeout = true
+		}
 
-		return false, record, resp
+		return false, record
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		log.Debugf("http request for function %s failed - error code: %s", function.Name, resp.Status)
 
-		record.ResponseTime = time.Since(start).Microseconds()
-		record.ConnectionTimeout = true
-
-		return false, record, resp
+	if statusCode == http.StatusAccepted {
+		// the action did not complete within the blocking window, so the duration is filled in from the activation
+		// record after the experiment
+		log.Debugf("OpenWhisk invocation of %s did not complete within the blocking window (activation %s)", function.Name, activation.ActivationID)
+		return true, record
 	}
 
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		log.Warnf("Failed to read output %s - %v", function.Name, err)
+	if !activation.Response.Success {
+		log.Debugf("OpenWhisk activation %s of %s failed - %s", activation.ActivationID, function.Name, activation.Response.Status)
 
-		record.ResponseTime = time.Since(start).Microseconds()
 		record.FunctionTimeout = true
-
-		return false, record, resp
+		return false, record
 	}
 
-	rawJson, err := base64.StdEncoding.DecodeString(string(bodyBytes))
-	if err != nil {
-		log.Warnf("Failed to decode base64 output %s - %v", function.Name, err)
-
-		record.ResponseTime = time.Since(start).Microseconds()
-		record.FunctionTimeout = true
-
-		return false, record, resp
+	record.ActualDuration = uint32(activation.Duration * 1000) // ms to μs
+	if instance := parseOpenWhiskResult(activation.Response.Result); instance != "" {
+		record.Instance = instance
 	}
 
-	var deserializedResponse FunctionResponse
-	err = json.Unmarshal(rawJson, &deserializedResponse)
-	if err != nil {
-		log.Warnf("Failed to deserialize response %s - %v", function.Name, err)
+	log.Tracef("(Replied)\t %s: %d[μs]", function.Name, record.ActualDuration)
+	log.Tracef("(E2E Latency) %s: %.2f[ms]\n", function.Name, float64(record.ResponseTime)/1e3)
 
-		record.ResponseTime = time.Since(start).Microseconds()
-		record.FunctionTimeout = true
+	return true, record
+}
 
-		return false, record, resp
+// parseOpenWhiskResult returns the function instance name reported by the workload, if any
+func parseOpenWhiskResult(result json.RawMessage) string {
+	var body struct {
+		Body []byte `json:"body"` // base64-encoded FunctionResponse
 	}
 
-	record.Instance = deserializedResponse.Function
-	record.ResponseTime = time.Since(start).Microseconds()
-	record.ActualDuration = uint32(deserializedResponse.ExecutionTime)
+	if err := json.Unmarshal(result, &body); err != nil || len(body.Body) == 0 {
+		return ""
+	}
 
-	return true, record, resp
-}
+	var response FunctionResponse
+	if err := json.Unmarshal(body.Body, &response); err != nil {
+		return ""
+	}
 
-func logInvocationSummary(function *common.Function, record *mc.ExecutionRecordBase, res *http.Response) {
-	log.Tracef("(Replied)\t %s: %d[ms]", function.Name, record.ActualDuration)
-	log.Tracef("(E2E Latency) %s: %.2f[ms]\n", function.Name, float64(record.ResponseTime)/1e3)
-	log.Tracef("(Client status code) %s: %d", function.Name, res.StatusCode)
+	return response.Function
 }
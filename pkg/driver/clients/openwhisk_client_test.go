@@ -0,0 +1,195 @@
+package clients
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+const (
+	testOpenWhiskUser     = "23bc46b1-71f6-4ed5-8c54-816aa4f8c502"
+	testOpenWhiskPassword = "123zO3xZCLrMN6v2BKK1dXYFpXlPkccOFqm12CdAsMgRU4VrNZ9lyGVCGuMDGIwP"
+)
+
+type fakeOpenWhisk struct {
+	mutex   sync.Mutex
+	actions map[string]openWhiskAction
+}
+
+func (f *fakeOpenWhisk) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if user, password, ok := r.BasicAuth(); !ok || user != testOpenWhiskUser || password != testOpenWhiskPassword {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	const actionPrefix = "/api/v1/namespaces/_/actions/"
+	const activationPrefix = "/api/v1/namespaces/_/activations/"
+
+	switch {
+	case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, actionPrefix):
+		var action openWhiskAction
+		_ = json.NewDecoder(r.Body).Decode(&action)
+		f.actions[strings.TrimPrefix(r.URL.Path, actionPrefix)] = action
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, actionPrefix):
+		delete(f.actions, strings.TrimPrefix(r.URL.Path, actionPrefix))
+	case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, actionPrefix):
+		if r.URL.Query().Get("blocking") != "true" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		var parameters map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&parameters)
+
+		switch name := strings.TrimPrefix(r.URL.Path, actionPrefix); name {
+		case "slow-function":
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte(`{"activationId":"slow-activation"}`))
+		case "failing-function":
+			// application errors are reported with 502 and the activation record
+			w.WriteHeader(http.StatusBadGateway)
+			_, _ = w.Write([]byte(`{"activationId":"failed-activation","response":{"status":"application error","success":false}}`))
+		default:
+			body, _ := json.Marshal(FunctionResponse{Status: "OK", Function: name, ExecutionTime: 1000})
+			result, _ := json.Marshal(map[string][]byte{"body": body})
+
+			_ = json.NewEncoder(w).Encode(OpenWhiskActivation{
+				ActivationID: "activation-" + parameters["cpu"],
+				Duration:     5,
+				Response: openWhiskActivationResponse{
+					Status:  "success",
+					Success: true,
+					Result:  result,
+				},
+			})
+		}
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, activationPrefix):
+		_ = json.NewEncoder(w).Encode(OpenWhiskActivation{
+			ActivationID: strings.TrimPrefix(r.URL.Path, activationPrefix),
+			Duration:     5,
+			Annotations: []openWhiskKeyValue{
+				{Key: "waitTime", Value: 12},
+				{Key: "initTime", Value: 300},
+				{Key: "kind", Value: "go:1.17"},
+			},
+		})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func createFakeOpenWhisk(t *testing.T) (*fakeOpenWhisk, *config.LoaderConfiguration) {
+	fake := &fakeOpenWhisk{actions: make(map[string]openWhiskAction)}
+
+	server := httptest.NewTLSServer(fake)
+	t.Cleanup(server.Close)
+
+	return fake, &config.LoaderConfiguration{
+		Platform:                   "OpenWhisk",
+		OpenWhiskAPIHost:           server.URL,
+		OpenWhiskAuthKey:           testOpenWhiskUser + ":" + testOpenWhiskPassword,
+		GRPCFunctionTimeoutSeconds: 5,
+	}
+}
+
+func TestOpenWhiskActionLifecycle(t *testing.T) {
+	fake, cfg := createFakeOpenWhisk(t)
+	client := NewOpenWhiskClient(cfg)
+
+	if err := client.CreateAction("test-function", "go:1.17", "package main"); err != nil {
+		t.Fatal(err)
+	}
+	if action, ok := fake.actions["test-function"]; !ok || action.Exec.Kind != "go:1.17" || action.Exec.Code != "package main" {
+		t.Error("Action has not been created.")
+	}
+
+	if err := client.DeleteAction("test-function"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := fake.actions["test-function"]; ok {
+		t.Error("Action has not been deleted.")
+	}
+
+	client.password = "wrong"
+	if err := client.CreateAction("test-function", "go:1.17", "package main"); err == nil {
+		t.Error("Expected the request to be rejected with wrong credentials.")
+	}
+}
+
+func TestOpenWhiskInvoker(t *testing.T) {
+	_, cfg := createFakeOpenWhisk(t)
+	client := NewOpenWhiskClient(cfg)
+	invocations := common.NewLockFreeQueue[*OpenWhiskInvocation]()
+
+	invoker := CreateInvoker(cfg, nil, invocations)
+
+	function := &common.Function{Name: "test-function", Endpoint: client.ActionURL("test-function")}
+	success, record := invoker.Invoke(function, &testRuntimeSpecs)
+	if !success || record.ActualDuration != 5000 || record.Instance != "test-function" ||
+		record.RequestedDuration != uint32(testRuntimeSpecs.Runtime*1000) {
+
+		t.Error("Unexpected execution record for a completed activation.")
+	}
+
+	slowFunction := &common.Function{Name: "slow-function", Endpoint: client.ActionURL("slow-function")}
+	success, record = invoker.Invoke(slowFunction, &testRuntimeSpecs)
+	if !success || record.ActualDuration != 0 {
+		t.Error("Unexpected execution record for an activation exceeding the blocking window.")
+	}
+
+	failingFunction := &common.Function{Name: "failing-function", Endpoint: client.ActionURL("failing-function")}
+	if success, record = invoker.Invoke(failingFunction, &testRuntimeSpecs); success || !record.FunctionTimeout {
+		t.Error("Invocation of a failing action should fail.")
+	}
+
+	missingFunction := &common.Function{Name: "missing-function", Endpoint: cfg.OpenWhiskAPIHost + "/missing"}
+	if success, _ = invoker.Invoke(missingFunction, &testRuntimeSpecs); success {
+		t.Error("Invocation of a missing action should fail.")
+	}
+
+	if invocations.Length() != 3 {
+		t.Fatalf("Expected 3 activations to be recorded, got %d.", invocations.Length())
+	}
+
+	first, second, third := invocations.Dequeue(), invocations.Dequeue(), invocations.Dequeue()
+	if first.ActivationID != "activation-10" || first.HttpStatusCode != http.StatusOK ||
+		second.ActivationID != "slow-activation" || second.HttpStatusCode != http.StatusAccepted ||
+		third.ActivationID != "failed-activation" || third.HttpStatusCode != http.StatusBadGateway {
+
+		t.Error("Unexpected activations recorded.")
+	}
+}
+
+func TestOpenWhiskActivationMetadata(t *testing.T) {
+	_, cfg := createFakeOpenWhisk(t)
+	client := NewOpenWhiskClient(cfg)
+
+	activation, err := client.GetActivation("some-activation")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	metadata := activation.Metadata()
+	if activation.ActivationID != "some-activation" ||
+		metadata.Duration != 5 ||
+		metadata.WaitTime != 12 ||
+		metadata.InitTime != 300 ||
+		metadata.StartType != mc.Cold {
+
+		t.Error("Unexpected activation metadata.")
+	}
+
+	if (&OpenWhiskActivation{}).Metadata().StartType != mc.Hot {
+		t.Error("Activation without initTime annotation should be a warm start.")
+	}
+}
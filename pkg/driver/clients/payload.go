@@ -0,0 +1,62 @@
+package clients
+
+import (
+	"context"
+	"math/rand"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/stats"
+)
+
+const payloadAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// dagPayload is the data passed between the nodes of DAGs, which is shared by all invocations
+var dagPayload string
+
+// PrepareDAGPayload creates random data of the size of the largest payload passed between the nodes of DAGs, drawn
+// from a source with the given seed. It is called before the experiment, so that invocations only take a prefix of
+// it. The data consists of printable characters, so that it can be sent as a string in gRPC requests.
+func PrepareDAGPayload(maxBytes int, seed int64) {
+	r := rand.New(rand.NewSource(seed))
+
+	data := make([]byte, maxBytes)
+	for i := range data {
+		data[i] = payloadAlphabet[r.Intn(len(payloadAlphabet))]
+	}
+
+	dagPayload = string(data)
+}
+
+// DAGPayload returns data of the given size passed between the nodes of a DAG. Sizes beyond the prepared data are
+// filled on demand.
+func DAGPayload(sizeInBytes int) string {
+	if sizeInBytes <= len(dagPayload) {
+		return dagPayload[:sizeInBytes]
+	}
+
+	return strings.Repeat("x", sizeInBytes)
+}
+
+// payloadStatsHandler measures the time from the start of an invocation until its request has been sent
+type payloadStatsHandler struct {
+	start        time.Time
+	transferTime atomic.Int64
+}
+
+func (h *payloadStatsHandler) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return ctx
+}
+
+func (h *payloadStatsHandler) HandleRPC(_ context.Context, s stats.RPCStats) {
+	if payload, ok := s.(*stats.OutPayload); ok {
+		h.transferTime.Store(payload.SentTime.Sub(h.start).Microseconds())
+	}
+}
+
+func (h *payloadStatsHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (h *payloadStatsHandler) HandleConn(context.Context, stats.ConnStats) {}
@@ -0,0 +1,166 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"container/list"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	"github.com/vhive-serverless/loader/pkg/driver/clients"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+// parseDAGOrchestration returns whether the functions of DAGs invoke their successors themselves instead of the loader
+func parseDAGOrchestration(cfg *config.LoaderConfiguration) bool {
+	switch strings.ToLower(cfg.DAGOrchestration) {
+	case "", "client":
+		return false
+	case "server":
+		if cfg.VSwarm {
+			log.Fatalf("Server-side DAG orchestration is not supported by vSwarm functions.")
+		}
+		return true
+	default:
+		log.Fatalf("Unsupported DAG orchestration %s.", cfg.DAGOrchestration)
+		return false
+	}
+}
+
+// validateChainedDAG terminates the loader if a DAG cannot be invoked with server-side chaining, where each stage is
+// invoked by the function of its only predecessor
+func validateChainedDAG(dag *list.List) {
+	for element := dag.Front(); element != nil; element = element.Next() {
+		node := element.Value.(*common.Node)
+		if node.Predecessors > 1 {
+			log.Fatalf("Stage %s joins several predecessors, which server-side DAG orchestration does not support.", node.StageName())
+		}
+
+		for _, branch := range node.Branches {
+			validateChainedDAG(branch)
+		}
+	}
+}
+
+// invokeChain invokes the root of the DAG with the description of the stages after it, which the functions invoke
+// themselves, and collects the records of all the stages reported back. The stages that do not report back count as
+// failed. As the loader does not invoke the stages after the root, failed invocations are not retried.
+func (d *Driver) invokeChain(metadata *InvocationMetadata) {
+	element := metadata.RootFunction.Front()
+	root := element.Value.(*common.Node)
+
+	runtimeSpecification := metadata.RuntimeSpecification
+	if runtimeSpecification == nil {
+		runtimeSpecification = &root.Function.Specification.RuntimeSpecification[metadata.IatIndex]
+	}
+
+	nodes := map[string]*common.Node{root.StageName(): root}
+	success, record, hops := d.Invoker.(clients.ChainInvoker).InvokeChain(root.Function, runtimeSpecification, common.VHiveMetadata{
+		WorkflowId:   strings.TrimSuffix(root.DAG, ","),
+		InvocationId: metadata.InvocationID,
+		InvokedOn:    time.Now().UTC(),
+		Stage:        root.StageName(),
+		Next:         d.chainedStages(element, metadata.IatIndex, nodes),
+	})
+
+	stages := map[string]*dagStage{}
+	stages[root.StageName()] = d.collectChainedRecord(metadata, root, record, success, nil)
+
+	// the stages invoked by a function follow it, so their parents are already known
+	for _, hop := range hops {
+		node, ok := nodes[hop.Stage]
+		if !ok {
+			log.Warnf("Function %s reported unknown stage %s.", root.Function.Name, hop.Stage)
+			continue
+		}
+
+		success := !hop.FunctionTimeout && !hop.MemoryAllocationTimeout
+		stages[hop.Stage] = d.collectChainedRecord(metadata, node, hop, success, stages[hop.ParentStage])
+	}
+
+	// the stages after a failed one are never invoked and do not report back, so they are abandoned
+	for name, node := range nodes {
+		if _, ok := stages[name]; ok {
+			continue
+		}
+
+		log.Debugf("Stage %s of function %s with ID %s was abandoned.", name, node.Function.Name, metadata.InvocationID)
+		atomic.AddInt64(metadata.FailedCount, 1)
+		if metadata.DAGInvocation != nil {
+			metadata.DAGInvocation.abandonStage()
+		}
+	}
+}
+
+func (d *Driver) collectChainedRecord(metadata *InvocationMetadata, node *common.Node, record *mc.ExecutionRecord, success bool, parent *dagStage) *dagStage {
+	stage := d.collectRecord(metadata, node, record, success, parent)
+
+	if success {
+		atomic.AddInt64(metadata.SuccessCount, 1)
+	} else {
+		log.Errorf("Invocation with for function %s with ID %s failed.", node.Function.Name, metadata.InvocationID)
+		atomic.AddInt64(metadata.FailedCount, 1)
+	}
+
+	return stage
+}
+
+// chainedStages describes the stages after the given node for its function to invoke, collecting their nodes by name
+func (d *Driver) chainedStages(element *list.Element, iatIndex int, nodes map[string]*common.Node) []*common.ChainedStage {
+	var next []*list.Element
+	if element.Next() != nil {
+		next = append(next, element.Next())
+	}
+	for _, branch := range element.Value.(*common.Node).Branches {
+		next = append(next, branch.Front())
+	}
+
+	var result []*common.ChainedStage
+	for _, successor := range next {
+		node := successor.Value.(*common.Node)
+		runtimeSpecification := node.Function.Specification.RuntimeSpecification[iatIndex]
+		nodes[node.StageName()] = node
+
+		stage := &common.ChainedStage{
+			Stage:             node.StageName(),
+			Endpoint:          node.Function.Endpoint,
+			RuntimeInMilliSec: uint32(runtimeSpecification.Runtime),
+			MemoryInMebiBytes: uint32(runtimeSpecification.Memory),
+			PayloadBytes:      payloadSize(successor, iatIndex, 1),
+			Next:              d.chainedStages(successor, iatIndex, nodes),
+		}
+		if strings.Contains(strings.ToLower(d.Configuration.LoaderConfiguration.Platform), "dirigent") {
+			stage.Authority = node.Function.ServiceName()
+		}
+
+		result = append(result, stage)
+	}
+
+	return result
+}
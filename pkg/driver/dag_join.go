@@ -0,0 +1,156 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+// dagJoins keeps track of the predecessors of the joins of a single invocation of a DAG that have completed
+type dagJoins struct {
+	mutex sync.Mutex
+	// completed and failed predecessors of each join, identified by the branch it is the front of
+	completed map[*list.List]int
+	failed    map[*list.List]int
+}
+
+// arrive records that a predecessor of the join at the front of the branch has completed or will never complete. It
+// returns whether it was the last predecessor of the join and how many of them failed.
+func (j *dagJoins) arrive(branch *list.List, succeeded bool) (bool, int) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	if j.completed == nil {
+		j.completed = make(map[*list.List]int)
+		j.failed = make(map[*list.List]int)
+	}
+
+	j.completed[branch]++
+	if !succeeded {
+		j.failed[branch]++
+	}
+
+	return j.completed[branch] == branch.Front().Value.(*common.Node).Predecessors, j.failed[branch]
+}
+
+func parseJoinPolicy(policy string) common.JoinPolicy {
+	switch strings.ToLower(policy) {
+	case "", "fail":
+		return common.JoinFail
+	case "skip":
+		return common.JoinSkip
+	case "partial":
+		return common.JoinPartial
+	default:
+		log.Fatalf("Unsupported DAG join policy %s.", policy)
+		return common.JoinFail
+	}
+}
+
+// resolveJoin returns whether the DAG continues at a join whose predecessors have all completed, and whether the join
+// itself is skipped
+func resolveJoin(policy common.JoinPolicy, predecessors int, failed int) (bool, bool) {
+	if failed == 0 {
+		return true, false
+	}
+
+	switch policy {
+	case common.JoinSkip:
+		return true, true
+	case common.JoinPartial:
+		return failed < predecessors, false
+	default:
+		return false, false
+	}
+}
+
+// followBranches starts the branches of a node once it has completed. Joins are started by their last predecessor,
+// and the nodes of branches that are not started are abandoned, so that the joins after them do not wait forever.
+func (d *Driver) followBranches(metadata *InvocationMetadata, branches []*list.List, succeeded bool, parent *dagStage) {
+	for _, branch := range branches {
+		start, skip := succeeded, false
+
+		payloadBytes := 0
+		if succeeded {
+			payloadBytes = payloadSize(branch.Front(), metadata.IatIndex, 1)
+		}
+
+		if predecessors := branch.Front().Value.(*common.Node).Predecessors; predecessors > 1 {
+			last, failed := metadata.Joins.arrive(branch, succeeded)
+			if !last {
+				continue
+			}
+
+			start, skip = resolveJoin(d.joinPolicy, predecessors, failed)
+			// the predecessors that succeeded pass the sizes sampled first, regardless of the order they completed in
+			payloadBytes = payloadSize(branch.Front(), metadata.IatIndex, predecessors-failed)
+		}
+
+		if !start {
+			d.abandonBranch(metadata, branch.Front(), parent)
+			continue
+		}
+
+		newMetadataValue := *metadata
+		newMetadata := &newMetadataValue
+		newMetadata.RootFunction = branch
+		newMetadata.RuntimeSpecification = nil
+		newMetadata.SkipRootFunction = skip
+		newMetadata.PayloadBytes = payloadBytes
+		newMetadata.Parent = parent
+		if newMetadata.DAGInvocation != nil {
+			newMetadata.DAGInvocation.addBranch()
+		}
+		newMetadata.AnnounceDoneWG.Add(1)
+		go d.invokeFunction(newMetadata)
+	}
+}
+
+// payloadSize returns the size of the data passed to the node by the given number of its predecessors in the
+// invocation of the DAG with the given index
+func payloadSize(node *list.Element, iatIndex int, predecessors int) int {
+	if node == nil || iatIndex >= len(node.Value.(*common.Node).PayloadSizes) {
+		return 0
+	}
+
+	size := 0
+	for _, predecessorSize := range node.Value.(*common.Node).PayloadSizes[iatIndex][:predecessors] {
+		size += predecessorSize
+	}
+
+	return size
+}
+
+// abandonBranch gives up on the nodes of a branch starting from the given one
+func (d *Driver) abandonBranch(metadata *InvocationMetadata, node *list.Element, parent *dagStage) {
+	for ; node != nil; node = node.Next() {
+		d.followBranches(metadata, node.Value.(*common.Node).Branches, false, parent)
+	}
+}
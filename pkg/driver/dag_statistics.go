@@ -0,0 +1,179 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package driver
+
+import (
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+// dagStage is a node executed by an invocation of a DAG
+type dagStage struct {
+	name   string
+	parent *dagStage
+
+	// in μs, where start and end are timestamps
+	start, end, responseTime int64
+}
+
+// dagInvocation keeps track of the stages executed by a single invocation of a DAG, so that its end-to-end latency
+// and critical path can be determined once all of its branches have completed
+type dagInvocation struct {
+	mutex sync.Mutex
+	// branches of the invocation that are being executed
+	active int
+	stages []*dagStage
+	failed int
+}
+
+func newDAGInvocation() *dagInvocation {
+	return &dagInvocation{active: 1}
+}
+
+func (i *dagInvocation) addBranch() {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	i.active++
+}
+
+// completeBranch returns whether the branch was the last one of the invocation being executed
+func (i *dagInvocation) completeBranch() bool {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	i.active--
+	return i.active == 0
+}
+
+func (i *dagInvocation) addStage(name string, parent *dagStage, record *mc.ExecutionRecord, success bool) *dagStage {
+	stage := &dagStage{
+		name:         name,
+		parent:       parent,
+		start:        record.StartTime,
+		end:          record.StartTime + record.ResponseTime,
+		responseTime: record.ResponseTime,
+	}
+
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	i.stages = append(i.stages, stage)
+	if !success {
+		i.failed++
+	}
+
+	return stage
+}
+
+// abandonStage records a stage of the invocation that was never executed as failed
+func (i *dagInvocation) abandonStage() {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	i.failed++
+}
+
+// record summarizes the invocation, whose critical path is the chain of stages that started each other and ends with
+// the stage that completed last
+func (i *dagInvocation) record() *mc.WorkflowRecord {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	if len(i.stages) == 0 {
+		return nil
+	}
+
+	start, last := i.stages[0].start, i.stages[0]
+	for _, stage := range i.stages[1:] {
+		start = min(start, stage.start)
+		if stage.end > last.end {
+			last = stage
+		}
+	}
+
+	record := &mc.WorkflowRecord{
+		StartTime:     start,
+		Latency:       last.end - start,
+		NodesExecuted: len(i.stages),
+		FailedNodes:   i.failed,
+	}
+
+	var path []string
+	for stage := last; stage != nil; stage = stage.parent {
+		path = append([]string{stage.name}, path...)
+		record.CriticalPathLength += stage.responseTime
+	}
+	record.CriticalPath = strings.Join(path, " -> ")
+
+	return record
+}
+
+// completeDAGBranch writes the record of the DAG invocation once its last branch has completed
+func (d *Driver) completeDAGBranch(metadata *InvocationMetadata) {
+	if metadata.DAGInvocation == nil || !metadata.DAGInvocation.completeBranch() {
+		return
+	}
+
+	record := metadata.DAGInvocation.record()
+	if record == nil {
+		return
+	}
+
+	record.Phase = int(metadata.Phase)
+	record.DAG = strings.TrimSuffix(metadata.RootFunction.Front().Value.(*common.Node).DAG, ",")
+	record.InvocationID = metadata.InvocationID
+
+	d.workflowRecords.Enqueue(record)
+}
+
+func (d *Driver) writeWorkflowRecordsToLog() {
+	records := make(chan interface{}, d.workflowRecords.Length())
+	writerDone := sync.WaitGroup{}
+	writerDone.Add(1)
+	go mc.RunCSVWriter(records, d.outputFilename("workflow"), &writerDone)
+
+	var latency, criticalPath float64
+	invocations := d.workflowRecords.Length()
+	for d.workflowRecords.Length() > 0 {
+		record := d.workflowRecords.Dequeue()
+
+		latency += float64(record.Latency)
+		criticalPath += float64(record.CriticalPathLength)
+		records <- record
+	}
+
+	close(records)
+	writerDone.Wait()
+
+	if invocations > 0 {
+		log.Infof("Workflow invocations: %d, average end-to-end latency %.2f ms, average critical path %.2f ms",
+			invocations, latency/float64(invocations)/1e3, criticalPath/float64(invocations)/1e3)
+	}
+}
@@ -0,0 +1,38 @@
+package driver
+
+import (
+	"testing"
+
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+func TestDAGInvocationRecord(t *testing.T) {
+	invocation := newDAGInvocation()
+
+	recordOf := func(start, responseTime int64) *mc.ExecutionRecord {
+		return &mc.ExecutionRecord{ExecutionRecordBase: mc.ExecutionRecordBase{StartTime: start, ResponseTime: responseTime}}
+	}
+
+	// split starts two mappers, where the slower one starts reduce
+	split := invocation.addStage("split", nil, recordOf(1000, 100), true)
+	invocation.addBranch()
+	fastMapper := invocation.addStage("map-1", split, recordOf(1110, 50), true)
+	slowMapper := invocation.addStage("map-2", split, recordOf(1120, 300), true)
+	invocation.addStage("reduce", slowMapper, recordOf(1450, 20), true)
+	invocation.addStage("store", fastMapper, recordOf(1200, 10), false)
+
+	if invocation.completeBranch() {
+		t.Fatal("The invocation should wait for its second branch.")
+	}
+	if !invocation.completeBranch() {
+		t.Fatal("The invocation should be complete after its last branch.")
+	}
+
+	record := invocation.record()
+	if record.StartTime != 1000 || record.Latency != 470 || record.NodesExecuted != 5 || record.FailedNodes != 1 {
+		t.Errorf("Unexpected record %+v.", record)
+	}
+	if record.CriticalPath != "split -> map-2 -> reduce" || record.CriticalPathLength != 420 {
+		t.Errorf("Unexpected critical path %s of length %d.", record.CriticalPath, record.CriticalPathLength)
+	}
+}
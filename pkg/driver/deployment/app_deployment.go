@@ -0,0 +1,94 @@
+package deployment
+
+import (
+	"fmt"
+	"math"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+// appService is a single service shared by all functions of a HashApp
+type appService struct {
+	service   *common.Function
+	functions []*common.Function
+}
+
+// groupFunctionsByApp groups functions by their HashApp, preserving the order in which apps first appear, and
+// creates the service each group is deployed as. Functions without invocation statistics form an app of their own.
+func groupFunctionsByApp(functions []*common.Function) []*appService {
+	var result []*appService
+	appIndex := make(map[string]int)
+
+	for _, function := range functions {
+		hashApp := function.Name
+		if function.InvocationStats != nil && function.InvocationStats.HashApp != "" {
+			hashApp = function.InvocationStats.HashApp
+		}
+
+		index, ok := appIndex[hashApp]
+		if !ok {
+			index = len(result)
+			appIndex[hashApp] = index
+
+			result = append(result, &appService{})
+		}
+
+		result[index].functions = append(result[index].functions, function)
+	}
+
+	for i, app := range result {
+		app.service = aggregateAppService(fmt.Sprintf("%s-app-%d", common.FunctionNamePrefix, i), app.functions)
+	}
+
+	log.Infof("Deploying %d functions as %d app-level services", len(functions), len(result))
+
+	return result
+}
+
+// aggregateAppService sums the resource requests and limits of the functions, as the service has to accommodate all
+// of them, while the initial scale and the cold start busy loop are the maximum over the functions. The average
+// runtime is weighted by the number of invocations of each function. All functions of an app have the same owner, so
+// the service is deployed in their tenant.
+func aggregateAppService(name string, functions []*common.Function) *common.Function {
+	service := &common.Function{
+		Name:             name,
+		Tenant:           functions[0].Tenant,
+		DirigentMetadata: functions[0].DirigentMetadata,
+		RuntimeStats:     &common.FunctionRuntimeStats{},
+	}
+
+	totalRuntime, totalCount := 0.0, 0.0
+	for _, function := range functions {
+		service.CPURequestsMilli += function.CPURequestsMilli
+		service.CPULimitsMilli += function.CPULimitsMilli
+		service.MemoryRequestsMiB += function.MemoryRequestsMiB
+
+		service.InitialScale = common.MaxOf(service.InitialScale, function.InitialScale)
+		service.ColdStartBusyLoopMs = common.MaxOf(service.ColdStartBusyLoopMs, function.ColdStartBusyLoopMs)
+
+		if function.RuntimeStats == nil {
+			continue
+		}
+
+		count := math.Max(function.RuntimeStats.Count, 1)
+		totalRuntime += function.RuntimeStats.Average * count
+		totalCount += count
+
+		service.RuntimeStats.HashApp = function.RuntimeStats.HashApp
+	}
+
+	if totalCount > 0 {
+		service.RuntimeStats.Average = totalRuntime / totalCount
+	}
+
+	return service
+}
+
+// propagateEndpoint routes the functions of the app to the deployed service
+func (app *appService) propagateEndpoint() {
+	for _, function := range app.functions {
+		function.Endpoint = app.service.Endpoint
+		function.AppService = app.service.Name
+	}
+}
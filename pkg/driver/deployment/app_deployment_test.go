@@ -0,0 +1,61 @@
+package deployment
+
+import (
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+func createAppFunction(name, hashApp string, cpu, memory, initialScale int, runtime, count float64) *common.Function {
+	return &common.Function{
+		Name:              name,
+		InvocationStats:   &common.FunctionInvocationStats{HashApp: hashApp},
+		RuntimeStats:      &common.FunctionRuntimeStats{HashApp: hashApp, Average: runtime, Count: count},
+		CPURequestsMilli:  cpu,
+		CPULimitsMilli:    2 * cpu,
+		MemoryRequestsMiB: memory,
+		InitialScale:      initialScale,
+	}
+}
+
+func TestGroupFunctionsByApp(t *testing.T) {
+	functions := []*common.Function{
+		createAppFunction("f0", "app-a", 100, 128, 1, 100, 1),
+		createAppFunction("f1", "app-b", 200, 256, 0, 50, 10),
+		createAppFunction("f2", "app-a", 300, 512, 3, 300, 3),
+		{Name: "f3"},
+	}
+
+	apps := groupFunctionsByApp(functions)
+	if len(apps) != 3 {
+		t.Fatalf("Expected 3 apps, got %d.", len(apps))
+	}
+
+	appA := apps[0]
+	if len(appA.functions) != 2 || appA.functions[0] != functions[0] || appA.functions[1] != functions[2] {
+		t.Fatal("Functions of app-a not grouped together.")
+	}
+
+	service := appA.service
+	if service.CPURequestsMilli != 400 || service.CPULimitsMilli != 800 || service.MemoryRequestsMiB != 640 ||
+		service.InitialScale != 3 || service.RuntimeStats.Average != 250 {
+
+		t.Errorf("Unexpected aggregated service %+v (runtime %f).", service, service.RuntimeStats.Average)
+	}
+
+	if apps[0].service.Name == apps[1].service.Name || apps[1].service.Name == apps[2].service.Name {
+		t.Error("App services should have distinct names.")
+	}
+
+	service.Endpoint = "app-a.default.example.com:80"
+	appA.propagateEndpoint()
+
+	for _, function := range appA.functions {
+		if function.Endpoint != service.Endpoint || function.ServiceName() != service.Name {
+			t.Errorf("Function %s not routed to the service of its app.", function.Name)
+		}
+	}
+	if functions[1].ServiceName() != "f1" {
+		t.Error("Functions of other apps should not be routed to the service.")
+	}
+}
@@ -36,15 +36,27 @@ func newDirigentDeployerConfiguration(cfg *config.Configuration) dirigentDeploym
 func (*dirigentDeployer) Deploy(cfg *config.Configuration) {
 	dirigentConfig := newDirigentDeployerConfiguration(cfg)
 
+	functions := cfg.Functions
+
+	var apps []*appService
+	if cfg.LoaderConfiguration.AppLevelDeployment {
+		apps = groupFunctionsByApp(cfg.Functions)
+
+		functions = make([]*common.Function, len(apps))
+		for i, app := range apps {
+			functions[i] = app.service
+		}
+	}
+
 	wg := &sync.WaitGroup{}
-	wg.Add(len(cfg.Functions))
+	wg.Add(len(functions))
 
-	for i := 0; i < len(cfg.Functions); i++ {
+	for i := 0; i < len(functions); i++ {
 		go func(idx int) {
 			defer wg.Done()
 
 			deployDirigent(
-				cfg.Functions[idx],
+				functions[idx],
 				dirigentConfig.RegistrationServer,
 				cfg.LoaderConfiguration.BusyLoopOnSandboxStartup,
 				cfg.LoaderConfiguration.PrepullMode,
@@ -54,6 +66,10 @@ func (*dirigentDeployer) Deploy(cfg *config.Configuration) {
 	}
 
 	wg.Wait()
+
+	for _, app := range apps {
+		app.propagateEndpoint()
+	}
 }
 
 func (*dirigentDeployer) Clean() {}
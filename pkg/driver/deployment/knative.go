@@ -11,6 +11,7 @@ import (
 	"regexp"
 	"runtime"
 	"strconv"
+	"strings"
 	"sync"
 )
 
@@ -23,7 +24,9 @@ var (
 	urlRegex = regexp.MustCompile("at URL:\nhttp://([^\n]+)")
 )
 
-type knativeDeployer struct{}
+type knativeDeployer struct {
+	tenants []config.TenantConfiguration
+}
 
 type knativeDeploymentConfiguration struct {
 	YamlPath          string
@@ -45,34 +48,63 @@ func newKnativeDeployerConfiguration(cfg *config.Configuration) knativeDeploymen
 	}
 }
 
-func (*knativeDeployer) Deploy(cfg *config.Configuration) {
+func (kd *knativeDeployer) Deploy(cfg *config.Configuration) {
 	knativeConfig := newKnativeDeployerConfiguration(cfg)
 
+	kd.tenants = cfg.Tenants
+	for _, tenant := range kd.tenants {
+		knativeCreateTenantNamespace(tenant)
+	}
+
+	if cfg.LoaderConfiguration.AppLevelDeployment {
+		apps := groupFunctionsByApp(cfg.Functions)
+
+		knativeDeployConcurrently(len(apps), func(i int) {
+			knativeDeploySingleFunction(
+				apps[i].service,
+				knativeConfig.YamlPath,
+				knativeConfig.IsPartiallyPanic,
+				knativeConfig.EndpointPort,
+				knativeConfig.AutoscalingMetric,
+			)
+
+			apps[i].propagateEndpoint()
+		})
+
+		return
+	}
+
+	knativeDeployConcurrently(len(cfg.Functions), func(i int) {
+		knativeDeploySingleFunction(
+			cfg.Functions[i],
+			knativeConfig.YamlPath,
+			knativeConfig.IsPartiallyPanic,
+			knativeConfig.EndpointPort,
+			knativeConfig.AutoscalingMetric,
+		)
+	})
+}
+
+func knativeDeployConcurrently(count int, deploy func(i int)) {
 	queue := make(chan struct{}, runtime.NumCPU()) // message queue as a sync method
 	deployed := sync.WaitGroup{}
-	deployed.Add(len(cfg.Functions))
+	deployed.Add(count)
 
-	for i := 0; i < len(cfg.Functions); i++ {
+	for i := 0; i < count; i++ {
 		go func() {
 			queue <- struct{}{}
 
 			defer deployed.Done()
 			defer func() { <-queue }()
 
-			knativeDeploySingleFunction(
-				cfg.Functions[i],
-				knativeConfig.YamlPath,
-				knativeConfig.IsPartiallyPanic,
-				knativeConfig.EndpointPort,
-				knativeConfig.AutoscalingMetric,
-			)
+			deploy(i)
 		}()
 	}
 
 	deployed.Wait()
 }
 
-func (*knativeDeployer) Clean() {
+func (kd *knativeDeployer) Clean() {
 	cmd := exec.Command("kn", "service", "delete", "--all")
 
 	var out bytes.Buffer
@@ -81,6 +113,45 @@ func (*knativeDeployer) Clean() {
 	if err := cmd.Run(); err != nil {
 		log.Errorf("Unable to delete Knative services - %s", err)
 	}
+
+	for _, tenant := range kd.tenants {
+		// deleting the namespace deletes the services and the quota of the tenant as well
+		if out, err := exec.Command("kubectl", "delete", "namespace", tenant.Name).CombinedOutput(); err != nil {
+			log.Errorf("Unable to delete namespace of tenant %s - %s\n%s", tenant.Name, err, out)
+		}
+	}
+}
+
+// knativeCreateTenantNamespace creates the namespace of the tenant and limits the resources its functions can request
+func knativeCreateTenantNamespace(tenant config.TenantConfiguration) {
+	commands := []string{
+		fmt.Sprintf("kubectl create namespace %s --dry-run=client -o yaml | kubectl apply -f -", tenant.Name),
+	}
+
+	var hard []string
+	if tenant.Quota.CPUMilli > 0 {
+		hard = append(hard, fmt.Sprintf("requests.cpu=%dm", tenant.Quota.CPUMilli))
+	}
+	if tenant.Quota.MemoryMiB > 0 {
+		hard = append(hard, fmt.Sprintf("requests.memory=%dMi", tenant.Quota.MemoryMiB))
+	}
+	if tenant.Quota.Pods > 0 {
+		hard = append(hard, fmt.Sprintf("pods=%d", tenant.Quota.Pods))
+	}
+	if len(hard) > 0 {
+		commands = append(commands, fmt.Sprintf("kubectl create quota %s-quota -n %s --hard=%s --dry-run=client -o yaml | kubectl apply -f -",
+			tenant.Name, tenant.Name, strings.Join(hard, ",")))
+	}
+
+	for _, command := range commands {
+		stdoutStderr, err := exec.Command("bash", "-c", command).CombinedOutput()
+		log.Debug("CMD response: ", string(stdoutStderr))
+		if err != nil {
+			log.Fatalf("Failed to create namespace of tenant %s: %v\n%s\n", tenant.Name, err, stdoutStderr)
+		}
+	}
+
+	log.Debugf("Created namespace of tenant %s with quota %v\n", tenant.Name, hard)
 }
 
 func knativeDeploySingleFunction(function *common.Function, yamlPath string, isPartiallyPanic bool, endpointPort int, autoscalingMetric string) bool {
@@ -90,6 +161,11 @@ func knativeDeploySingleFunction(function *common.Function, yamlPath string, isP
 		panicWindow = "\"100.0\""
 		panicThreshold = "\"1000.0\""
 	}
+	functionNamespace := namespace
+	if function.Tenant != "" {
+		functionNamespace = function.Tenant
+	}
+
 	autoscalingTarget := 100 // default for concurrency
 	if autoscalingMetric == "rps" {
 		autoscalingTarget = int(math.Round(1000.0 / function.RuntimeStats.Average))
@@ -114,6 +190,8 @@ func knativeDeploySingleFunction(function *common.Function, yamlPath string, isP
 		wrapString(strconv.Itoa(autoscalingTarget)),
 
 		wrapString(strconv.Itoa(function.ColdStartBusyLoopMs)),
+
+		functionNamespace,
 	)
 
 	stdoutStderr, err := cmd.CombinedOutput()
@@ -128,7 +206,7 @@ func knativeDeploySingleFunction(function *common.Function, yamlPath string, isP
 		log.Debugf("Update function endpoint to %s\n", endpoint)
 		function.Endpoint = endpoint
 	} else {
-		function.Endpoint = fmt.Sprintf("%s.%s.%s", function.Name, namespace, bareMetalLbGateway)
+		function.Endpoint = fmt.Sprintf("%s.%s.%s", function.Name, functionNamespace, bareMetalLbGateway)
 	}
 	// adding port to the endpoint
 	function.Endpoint = fmt.Sprintf("%s:%d", function.Endpoint, endpointPort)
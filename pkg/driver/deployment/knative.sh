@@ -14,4 +14,6 @@ export AUTOSCALING_TARGET=${10}
 
 export COLD_START_BUSY_LOOP_MS=${11}
 
-cat $CONFIG_FILE | envsubst | kn service apply $FUNC_NAME --scale-init $INIT_SCALE --concurrency-target 1 --wait-timeout 2000000 -f /dev/stdin
\ No newline at end of file
+NAMESPACE=${12}
+
+cat $CONFIG_FILE | envsubst | kn service apply $FUNC_NAME -n $NAMESPACE --scale-init $INIT_SCALE --concurrency-target 1 --wait-timeout 2000000 -f /dev/stdin
\ No newline at end of file
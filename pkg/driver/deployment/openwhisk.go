@@ -1,17 +1,21 @@
 package deployment
 
 import (
-	"bytes"
-	"fmt"
-	"github.com/vhive-serverless/loader/pkg/config"
-	"os/exec"
-	"strings"
+	"os"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	"github.com/vhive-serverless/loader/pkg/driver/clients"
+)
+
+const (
+	openWhiskActionLocation = "./pkg/workload/openwhisk/workload_openwhisk.go"
+	openWhiskActionKind     = "go:1.17"
 )
 
 type openWhiskDeployer struct {
+	client    *clients.OpenWhiskClient
 	functions []*common.Function
 }
 
@@ -20,43 +24,31 @@ func newOpenWhiskDeployer() *openWhiskDeployer {
 }
 
 func (owd *openWhiskDeployer) Deploy(cfg *config.Configuration) {
-	owd.functions = cfg.Functions
-
-	cmd := exec.Command("wsk", "-i", "property", "get", "--apihost")
+	if cfg.LoaderConfiguration.OpenWhiskAPIHost == "" || cfg.LoaderConfiguration.OpenWhiskAuthKey == "" {
+		log.Fatal("OpenWhiskAPIHost and OpenWhiskAuthKey have to be set to deploy functions on OpenWhisk.")
+	}
 
-	var out bytes.Buffer
-	cmd.Stdout = &out
+	owd.client = clients.NewOpenWhiskClient(cfg.LoaderConfiguration)
+	owd.functions = cfg.Functions
 
-	err := cmd.Run()
+	code, err := os.ReadFile(openWhiskActionLocation)
 	if err != nil {
-		log.Fatalf("Unable to read OpenWhisk API host data - %s", err)
+		log.Fatalf("Unable to read OpenWhisk action source code - %s", err)
 	}
-	result := strings.Split(out.String(), "\t")
-	endpoint := strings.TrimSpace(result[len(result)-1])
-
-	const actionLocation = "./pkg/workload/openwhisk/workload_openwhisk.go"
 
 	for i := 0; i < len(owd.functions); i++ {
-		cmd = exec.Command("wsk", "-i", "action", "create", owd.functions[i].Name, actionLocation, "--kind", "go:1.17", "--web", "true")
-
-		err = cmd.Run()
+		err = owd.client.CreateAction(owd.functions[i].Name, openWhiskActionKind, string(code))
 		if err != nil {
 			log.Fatalf("Unable to create OpenWhisk action for function %s - %s", owd.functions[i].Name, err)
 		}
 
-		owd.functions[i].Endpoint = fmt.Sprintf("https://%s/api/v1/web/guest/default/%s", endpoint, owd.functions[i].Name)
+		owd.functions[i].Endpoint = owd.client.ActionURL(owd.functions[i].Name)
 	}
 }
 
 func (owd *openWhiskDeployer) Clean() {
 	for i := 0; i < len(owd.functions); i++ {
-		// TODO: check if there is a command such as "... delete --all"
-		cmd := exec.Command("wsk", "-i", "action", "delete", owd.functions[i].Name)
-
-		var out bytes.Buffer
-		cmd.Stdout = &out
-
-		err := cmd.Run()
+		err := owd.client.DeleteAction(owd.functions[i].Name)
 		if err != nil {
 			log.Debugf("Unable to delete OpenWhisk action for function %s - %s", owd.functions[i].Name, err)
 		}
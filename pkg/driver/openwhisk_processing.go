@@ -0,0 +1,92 @@
+package driver
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/driver/clients"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+const (
+	openWhiskActivationBatchSize = 50
+	// activation records are persisted asynchronously by OpenWhisk, so they might not be available right away
+	openWhiskActivationRetries = 3
+)
+
+func (d *Driver) writeOpenWhiskActivationsToLog() {
+	client := clients.NewOpenWhiskClient(d.Configuration.LoaderConfiguration)
+
+	records := make(chan interface{}, openWhiskActivationBatchSize)
+	writerDone := sync.WaitGroup{}
+	writerDone.Add(1)
+	go mc.RunCSVWriter(records, d.outputFilename("activation"), &writerDone)
+
+	currentBatch := 0
+	totalBatches := int(math.Ceil(float64(d.OpenWhiskInvocations.Length()) / float64(openWhiskActivationBatchSize)))
+
+	log.Infof("Gathering OpenWhisk activation metadata...")
+	for d.OpenWhiskInvocations.Length() > 0 {
+		currentBatch++
+
+		toProcess := openWhiskActivationBatchSize
+		if d.OpenWhiskInvocations.Length() < openWhiskActivationBatchSize {
+			toProcess = d.OpenWhiskInvocations.Length()
+		}
+
+		wg := sync.WaitGroup{}
+		wg.Add(toProcess)
+
+		for i := 0; i < toProcess; i++ {
+			go func() {
+				defer wg.Done()
+
+				records <- fetchOpenWhiskActivation(client, d.OpenWhiskInvocations.Dequeue())
+			}()
+		}
+
+		wg.Wait()
+
+		log.Infof("Processed %d/%d batches of OpenWhisk activations", currentBatch, totalBatches)
+	}
+
+	close(records)
+	writerDone.Wait()
+
+	log.Infof("Finished gathering OpenWhisk activation metadata")
+}
+
+func fetchOpenWhiskActivation(client *clients.OpenWhiskClient, invocation *clients.OpenWhiskInvocation) *mc.ExecutionRecordOpenWhisk {
+	record := &mc.ExecutionRecordOpenWhisk{
+		ExecutionRecordBase: invocation.Record.ExecutionRecordBase,
+		ActivationID:        invocation.ActivationID,
+		HttpStatusCode:      invocation.HttpStatusCode,
+	}
+
+	var activation *clients.OpenWhiskActivation
+	var err error
+
+	for i := 0; i < openWhiskActivationRetries; i++ {
+		if activation, err = client.GetActivation(invocation.ActivationID); err == nil {
+			break
+		}
+
+		time.Sleep(time.Second)
+	}
+
+	if err != nil {
+		log.Errorf("Failed to fetch OpenWhisk activation %s - %v", invocation.ActivationID, err)
+		return record
+	}
+
+	metadata := activation.Metadata()
+
+	record.ActualDuration = metadata.Duration * 1000 // ms to μs
+	record.StartType = metadata.StartType
+	record.WaitTime = metadata.WaitTime * 1000 // ms to μs
+	record.InitTime = metadata.InitTime * 1000 // ms to μs
+
+	return record
+}
@@ -0,0 +1,113 @@
+package driver
+
+import (
+	"sort"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+	"gonum.org/v1/gonum/stat"
+)
+
+type tenantAccumulator struct {
+	record *mc.TenantRecord
+
+	latencies []float64
+	instances map[string]struct{}
+}
+
+// tenantStatistics aggregates the execution records of each tenant to compare isolation and fairness across tenants
+type tenantStatistics struct {
+	mutex   sync.Mutex
+	tenants map[string]*tenantAccumulator
+	order   []string
+}
+
+func newTenantStatistics(tenants []config.TenantConfiguration, functions []*common.Function) *tenantStatistics {
+	s := &tenantStatistics{tenants: make(map[string]*tenantAccumulator)}
+
+	for _, tenant := range tenants {
+		s.tenants[tenant.Name] = &tenantAccumulator{
+			record:    &mc.TenantRecord{Tenant: tenant.Name},
+			instances: make(map[string]struct{}),
+		}
+		s.order = append(s.order, tenant.Name)
+	}
+
+	for _, function := range functions {
+		if accumulator, ok := s.tenants[function.Tenant]; ok {
+			accumulator.record.Functions++
+		}
+	}
+
+	return s
+}
+
+func (s *tenantStatistics) add(tenant string, record *mc.ExecutionRecord) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	accumulator, ok := s.tenants[tenant]
+	if !ok {
+		log.Warnf("Execution record of unknown tenant %s.", tenant)
+		return
+	}
+
+	accumulator.record.Invocations++
+	if record.Throttled {
+		accumulator.record.Throttled++
+	}
+	if record.ConnectionTimeout || record.FunctionTimeout {
+		accumulator.record.Failed++
+		return
+	}
+
+	accumulator.latencies = append(accumulator.latencies, float64(record.ResponseTime)/1e3)
+
+	// the first invocation served by an instance is its cold start
+	if _, ok := accumulator.instances[record.Instance]; !ok && record.Instance != "" {
+		accumulator.instances[record.Instance] = struct{}{}
+		accumulator.record.ColdStarts++
+	}
+}
+
+func (s *tenantStatistics) records() []*mc.TenantRecord {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var result []*mc.TenantRecord
+	for _, name := range s.order {
+		accumulator := s.tenants[name]
+
+		if len(accumulator.latencies) > 0 {
+			sort.Float64s(accumulator.latencies)
+
+			accumulator.record.AverageLatency = stat.Mean(accumulator.latencies, nil)
+			accumulator.record.P50Latency = stat.Quantile(0.5, stat.Empirical, accumulator.latencies, nil)
+			accumulator.record.P99Latency = stat.Quantile(0.99, stat.Empirical, accumulator.latencies, nil)
+		}
+
+		result = append(result, accumulator.record)
+	}
+
+	return result
+}
+
+func (d *Driver) writeTenantStatisticsToLog() {
+	records := make(chan interface{}, len(d.tenantStatistics.order))
+	writerDone := sync.WaitGroup{}
+	writerDone.Add(1)
+	go mc.RunCSVWriter(records, d.outputFilename("tenants"), &writerDone)
+
+	for _, record := range d.tenantStatistics.records() {
+		log.Infof("Tenant %s: %d invocations, %d failed, %d throttled, %d cold starts, p50 %.2f ms, p99 %.2f ms",
+			record.Tenant, record.Invocations, record.Failed, record.Throttled, record.ColdStarts, record.P50Latency, record.P99Latency)
+
+		records <- record
+	}
+
+	close(records)
+	writerDone.Wait()
+}
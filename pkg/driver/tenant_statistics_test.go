@@ -0,0 +1,77 @@
+package driver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	mc "github.com/vhive-serverless/loader/pkg/metric"
+)
+
+func TestTenantStatistics(t *testing.T) {
+	tenants := []config.TenantConfiguration{{Name: "tenant-a"}, {Name: "tenant-b"}}
+	functions := []*common.Function{
+		{Name: "f0", Tenant: "tenant-a"},
+		{Name: "f1", Tenant: "tenant-a"},
+		{Name: "f2", Tenant: "tenant-b"},
+	}
+
+	statistics := newTenantStatistics(tenants, functions)
+
+	recordOf := func(instance string, responseTimeMs int64, failed, throttled bool) *mc.ExecutionRecord {
+		return &mc.ExecutionRecord{ExecutionRecordBase: mc.ExecutionRecordBase{
+			Instance:          instance,
+			ResponseTime:      responseTimeMs * 1000,
+			ConnectionTimeout: failed,
+			Throttled:         throttled,
+		}}
+	}
+
+	statistics.add("tenant-a", recordOf("instance-0", 10, false, false))
+	statistics.add("tenant-a", recordOf("instance-0", 20, false, false))
+	statistics.add("tenant-a", recordOf("instance-1", 30, false, false))
+	statistics.add("tenant-a", recordOf("", 0, true, true))
+	statistics.add("tenant-b", recordOf("", 0, true, false))
+
+	records := statistics.records()
+	if len(records) != 2 {
+		t.Fatalf("Expected records of 2 tenants, got %d.", len(records))
+	}
+
+	a, b := records[0], records[1]
+	if a.Tenant != "tenant-a" || a.Functions != 2 || a.Invocations != 4 || a.Failed != 1 || a.Throttled != 1 ||
+		a.ColdStarts != 2 || a.AverageLatency != 20 || a.P50Latency != 20 || a.P99Latency != 30 {
+
+		t.Errorf("Unexpected record of tenant-a %+v.", a)
+	}
+	if b.Tenant != "tenant-b" || b.Functions != 1 || b.Invocations != 1 || b.Failed != 1 || b.Throttled != 0 ||
+		b.ColdStarts != 0 || b.AverageLatency != 0 {
+
+		t.Errorf("Unexpected record of tenant-b %+v.", b)
+	}
+}
+
+func TestTenantStatisticsOfAsyncRecords(t *testing.T) {
+	// the responses have not been completed, so the invocations count as failed once they are gathered
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	driver := createTestDriver(t, []int{1})
+	driver.Configuration.LoaderConfiguration.AsyncResponseURL = strings.TrimPrefix(server.URL, "http://")
+	driver.tenantStatistics = newTenantStatistics([]config.TenantConfiguration{{Name: "tenant-a"}}, nil)
+
+	for i := 0; i < 3; i++ {
+		driver.AsyncRecords.Enqueue(&mc.ExecutionRecord{AsyncResponseID: "id", Tenant: "tenant-a"})
+	}
+
+	logCh := make(chan *mc.ExecutionRecord, 3)
+	driver.writeAsyncRecordsToLog(logCh)
+
+	record := driver.tenantStatistics.records()[0]
+	if len(logCh) != 3 || record.Invocations != 3 || record.Failed != 3 {
+		t.Errorf("Unexpected record of tenant-a with %d gathered records %+v.", len(logCh), record)
+	}
+}
@@ -26,10 +26,9 @@ package driver
 
 import (
 	"container/list"
-	"encoding/json"
 	"fmt"
+	"math"
 	"os"
-	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -51,9 +50,22 @@ type Driver struct {
 	SpecificationGenerator *generator.SpecificationGenerator
 	Invoker                clients.Invoker
 
-	AsyncRecords          *common.LockFreeQueue[*mc.ExecutionRecord]
-	readOpenWhiskMetadata sync.Mutex
-	allFunctionsInvoked   sync.WaitGroup
+	AsyncRecords         *common.LockFreeQueue[*mc.ExecutionRecord]
+	OpenWhiskInvocations *common.LockFreeQueue[*clients.OpenWhiskInvocation]
+	allFunctionsInvoked  sync.WaitGroup
+
+	// per-function streams of IATs and runtime specifications when they are generated lazily
+	invocationStreams map[string]*generator.InvocationStream
+	// per-tenant results of multi-tenant experiments
+	tenantStatistics *tenantStatistics
+	// workflows of the workflow file executed in DAG mode instead of generated DAGs
+	workflows []*generator.Workflow
+	// how DAGs continue at joins with failed predecessors
+	joinPolicy common.JoinPolicy
+	// end-to-end records of the invocations of DAGs
+	workflowRecords *common.LockFreeQueue[*mc.WorkflowRecord]
+	// whether the functions of DAGs invoke their successors themselves instead of the loader
+	serverSideChaining bool
 }
 
 func NewDriver(driverConfig *config.Configuration) *Driver {
@@ -61,12 +73,27 @@ func NewDriver(driverConfig *config.Configuration) *Driver {
 		Configuration:          driverConfig,
 		SpecificationGenerator: generator.NewSpecificationGenerator(driverConfig.LoaderConfiguration.Seed),
 
-		AsyncRecords:          common.NewLockFreeQueue[*mc.ExecutionRecord](),
-		readOpenWhiskMetadata: sync.Mutex{},
-		allFunctionsInvoked:   sync.WaitGroup{},
+		AsyncRecords:         common.NewLockFreeQueue[*mc.ExecutionRecord](),
+		OpenWhiskInvocations: common.NewLockFreeQueue[*clients.OpenWhiskInvocation](),
+		allFunctionsInvoked:  sync.WaitGroup{},
+
+		joinPolicy:      parseJoinPolicy(driverConfig.LoaderConfiguration.DAGJoinPolicy),
+		workflowRecords: common.NewLockFreeQueue[*mc.WorkflowRecord](),
+
+		serverSideChaining: driverConfig.LoaderConfiguration.DAGMode && parseDAGOrchestration(driverConfig.LoaderConfiguration),
 	}
 
-	d.Invoker = clients.CreateInvoker(driverConfig.LoaderConfiguration, &d.allFunctionsInvoked, &d.readOpenWhiskMetadata)
+	d.SpecificationGenerator.SetIATShape(driverConfig.LoaderConfiguration.IATShape)
+	d.SpecificationGenerator.SetRuntimeMemoryCorrelation(driverConfig.LoaderConfiguration.RuntimeMemoryCorrelation)
+	d.SpecificationGenerator.SetBurstEvents(generator.NewBurstEvents(driverConfig.LoaderConfiguration.BurstEvents))
+	if driverConfig.IATDistribution == common.Empirical {
+		d.SpecificationGenerator.SetEmpiricalCDFs(generator.ReadEmpiricalCDFs(driverConfig.LoaderConfiguration.IATCDFPath))
+	}
+	d.SpecificationGenerator.SetArrivalModels(generator.NewArrivalModels(driverConfig.LoaderConfiguration.TriggerArrivalModels))
+	d.Invoker = clients.CreateInvoker(driverConfig.LoaderConfiguration, &d.allFunctionsInvoked, d.OpenWhiskInvocations)
+	if _, ok := d.Invoker.(clients.ChainInvoker); d.serverSideChaining && !ok {
+		log.Fatalf("Server-side DAG orchestration requires the gRPC protocol on Knative or Dirigent.")
+	}
 
 	return d
 }
@@ -88,6 +115,19 @@ type InvocationMetadata struct {
 
 	InvocationID string
 	IatIndex     int
+	// runtime specification of the root function when specifications are generated lazily, otherwise looked up by
+	// IatIndex
+	RuntimeSpecification *common.RuntimeSpecification
+	// joins of the DAG invocation, shared by all of its branches
+	Joins *dagJoins
+	// whether the root function is a join that is skipped, so the branch continues right after it
+	SkipRootFunction bool
+	// stages executed by the DAG invocation in DAG mode, shared by all of its branches
+	DAGInvocation *dagInvocation
+	// stage whose completion started the branch
+	Parent *dagStage
+	// size of the data passed to the root function by its predecessors
+	PayloadBytes int
 
 	SuccessCount        *int64
 	FailedCount         *int64
@@ -115,15 +155,46 @@ func composeInvocationID(timeGranularity common.TraceGranularity, minuteIndex in
 func (d *Driver) invokeFunction(metadata *InvocationMetadata) {
 	defer metadata.AnnounceDoneWG.Done()
 
+	if metadata.Joins == nil {
+		metadata.Joins = &dagJoins{}
+	}
+	if metadata.DAGInvocation == nil && d.Configuration.LoaderConfiguration.DAGMode {
+		metadata.DAGInvocation = newDAGInvocation()
+	}
+	defer d.completeDAGBranch(metadata)
+
+	if d.serverSideChaining {
+		d.invokeChain(metadata)
+		return
+	}
+
 	var success bool
+	var stage *dagStage
+	parent := metadata.Parent
+	payloadBytes := metadata.PayloadBytes
 	node := metadata.RootFunction.Front()
 	var record *mc.ExecutionRecord
 	var runtimeSpecifications *common.RuntimeSpecification
-	var branches []*list.List
 	var invocationRetries int
 	for node != nil {
+		if metadata.SkipRootFunction && node == metadata.RootFunction.Front() {
+			d.followBranches(metadata, node.Value.(*common.Node).Branches, true, parent)
+			node = node.Next()
+			payloadBytes = payloadSize(node, metadata.IatIndex, 1)
+			continue
+		}
+
 		function := node.Value.(*common.Node).Function
-		runtimeSpecifications = &function.Specification.RuntimeSpecification[metadata.IatIndex]
+		if metadata.RuntimeSpecification != nil && node == metadata.RootFunction.Front() {
+			runtimeSpecifications = metadata.RuntimeSpecification
+		} else {
+			runtimeSpecifications = &function.Specification.RuntimeSpecification[metadata.IatIndex]
+		}
+		if payloadBytes > 0 {
+			specification := *runtimeSpecifications
+			specification.PayloadBytes = payloadBytes
+			runtimeSpecifications = &specification
+		}
 
 		success, record = d.Invoker.Invoke(function, runtimeSpecifications)
 
@@ -132,41 +203,69 @@ func (d *Driver) invokeFunction(metadata *InvocationMetadata) {
 			invocationRetries += 1
 			continue
 		}
-		record.Phase = int(metadata.Phase)
-		record.Instance = fmt.Sprintf("%s%s", node.Value.(*common.Node).DAG, record.Instance)
-		record.InvocationID = metadata.InvocationID
-
-		if !d.Configuration.LoaderConfiguration.AsyncMode || record.AsyncResponseID == "" {
-			metadata.RecordOutputChannel <- record
-		} else {
-			record.TimeToSubmitMs = record.ResponseTime
-			d.AsyncRecords.Enqueue(record)
-		}
-		atomic.AddInt64(metadata.FunctionsInvoked, 1)
+		stage = d.collectRecord(metadata, node.Value.(*common.Node), record, success, parent)
 		if !success {
 			log.Errorf("Invocation with for function %s with ID %s failed.", function.Name, metadata.InvocationID)
 			atomic.AddInt64(metadata.FailedCount, 1)
+			d.abandonBranch(metadata, node, stage)
 			break
 		}
 		atomic.AddInt64(metadata.SuccessCount, 1)
-		branches = node.Value.(*common.Node).Branches
-		for i := 0; i < len(branches); i++ {
-			newMetadataValue := *metadata
-			newMetadata := &newMetadataValue
-			newMetadata.RootFunction = branches[i]
-			newMetadata.AnnounceDoneWG.Add(1)
-			go d.invokeFunction(newMetadata)
-		}
+		d.followBranches(metadata, node.Value.(*common.Node).Branches, true, stage)
+		parent = stage
 
 		node = node.Next()
+		payloadBytes = payloadSize(node, metadata.IatIndex, 1)
 	}
 }
 
+// collectRecord completes the record of the invocation of a node and passes it on for writing, returning the stage
+// the node executed in DAG mode
+func (d *Driver) collectRecord(metadata *InvocationMetadata, node *common.Node, record *mc.ExecutionRecord, success bool, parent *dagStage) *dagStage {
+	var stage *dagStage
+
+	record.Phase = int(metadata.Phase)
+	record.Instance = fmt.Sprintf("%s%s", node.DAG, record.Instance)
+	record.InvocationID = metadata.InvocationID
+	if metadata.DAGInvocation != nil {
+		record.Stage = node.StageName()
+		if parent != nil {
+			record.ParentStage = parent.name
+		}
+		stage = metadata.DAGInvocation.addStage(record.Stage, parent, record, success)
+	}
+
+	record.Tenant = node.Function.Tenant
+
+	if !d.Configuration.LoaderConfiguration.AsyncMode || record.AsyncResponseID == "" {
+		if d.tenantStatistics != nil {
+			d.tenantStatistics.add(record.Tenant, record)
+		}
+
+		metadata.RecordOutputChannel <- record
+	} else {
+		record.TimeToSubmitMs = record.ResponseTime
+		d.AsyncRecords.Enqueue(record)
+	}
+	atomic.AddInt64(metadata.FunctionsInvoked, 1)
+
+	return stage
+}
+
 func (d *Driver) functionsDriver(functionLinkedList *list.List, announceFunctionDone *sync.WaitGroup, addInvocationsToGroup *sync.WaitGroup, totalSuccessful *int64, totalFailed *int64, totalIssued *int64, recordOutputChannel chan *mc.ExecutionRecord) {
 	defer announceFunctionDone.Done()
 
 	function := functionLinkedList.Front().Value.(*common.Node).Function
-	invocationCount := len(function.Specification.IAT)
+	stream := d.invocationStreams[function.Name]
+
+	// with lazily generated specifications, IAT and runtimeSpecification only hold the invocations of the current time
+	// unit, so the total number of invocations is not known upfront
+	IAT, runtimeSpecification := function.Specification.IAT, function.Specification.RuntimeSpecification
+	if stream != nil {
+		IAT, runtimeSpecification, _ = stream.Next()
+	}
+
+	invocationCount := len(IAT)
 	addInvocationsToGroup.Add(invocationCount)
 
 	if invocationCount == 0 {
@@ -175,12 +274,21 @@ func (d *Driver) functionsDriver(functionLinkedList *list.List, announceFunction
 	}
 
 	// result statistics
-	minuteIndexSearch := common.NewIntervalSearch(function.Specification.PerMinuteCount)
-	interval := minuteIndexSearch.SearchInterval(0)
-	minuteIndexEnd, minuteIndex, invocationSinceTheBeginningOfMinute := interval.End, interval.Value, 0
+	var minuteIndexSearch *common.IntervalSearch
+	var interval *common.Interval[int]
+	minuteIndexEnd, minuteIndex, invocationSinceTheBeginningOfMinute := math.MaxInt, 0, 0
+	if stream != nil {
+		minuteIndex = stream.TimeUnit()
+	} else {
+		minuteIndexSearch = common.NewIntervalSearch(function.Specification.PerMinuteCount)
+		interval = minuteIndexSearch.SearchInterval(0)
+		minuteIndexEnd, minuteIndex = interval.End, interval.Value
+	}
 
-	IAT := function.Specification.IAT
-	iatIndex, terminationIAT := 0, invocationCount
+	iatIndex, windowIndex, terminationIAT := 0, 0, invocationCount
+	if stream != nil {
+		terminationIAT = math.MaxInt
+	}
 
 	var successfulInvocations int64
 	var failedInvocations int64
@@ -198,13 +306,21 @@ func (d *Driver) functionsDriver(functionLinkedList *list.List, announceFunction
 	var previousIATSum int64
 
 	for {
-		if iatIndex >= len(IAT) || iatIndex >= terminationIAT {
+		if windowIndex >= len(IAT) && stream != nil {
+			var ok bool
+			if IAT, runtimeSpecification, ok = stream.Next(); ok {
+				addInvocationsToGroup.Add(len(IAT))
+				windowIndex, minuteIndex, invocationSinceTheBeginningOfMinute = 0, stream.TimeUnit(), 0
+			}
+		}
+
+		if windowIndex >= len(IAT) || iatIndex >= terminationIAT {
 			break // end of experiment for this individual function driver
 		}
 
 		d.announceWarmupEnd(minuteIndex, &currentPhase)
 
-		iat := time.Duration(IAT[iatIndex]) * time.Microsecond
+		iat := time.Duration(IAT[windowIndex]) * time.Microsecond
 
 		schedulingDelay := time.Since(startOfExperiment).Microseconds() - previousIATSum
 		sleepFor := iat.Microseconds() - schedulingDelay
@@ -213,18 +329,24 @@ func (d *Driver) functionsDriver(functionLinkedList *list.List, announceFunction
 		previousIATSum += iat.Microseconds()
 
 		if !d.Configuration.TestMode {
+			var runtimeSpecificationOverride *common.RuntimeSpecification
+			if stream != nil {
+				runtimeSpecificationOverride = &runtimeSpecification[windowIndex]
+			}
+
 			waitForInvocations.Add(1)
 			go d.invokeFunction(&InvocationMetadata{
-				RootFunction:        functionLinkedList,
-				Phase:               currentPhase,
-				InvocationID:        composeInvocationID(d.Configuration.TraceGranularity, minuteIndex, invocationSinceTheBeginningOfMinute),
-				IatIndex:            iatIndex,
-				SuccessCount:        &successfulInvocations,
-				FailedCount:         &failedInvocations,
-				FunctionsInvoked:    &functionsInvoked,
-				RecordOutputChannel: recordOutputChannel,
-				AnnounceDoneWG:      &waitForInvocations,
-				AnnounceDoneExe:     addInvocationsToGroup,
+				RootFunction:         functionLinkedList,
+				Phase:                currentPhase,
+				InvocationID:         composeInvocationID(d.Configuration.TraceGranularity, minuteIndex, invocationSinceTheBeginningOfMinute),
+				IatIndex:             iatIndex,
+				RuntimeSpecification: runtimeSpecificationOverride,
+				SuccessCount:         &successfulInvocations,
+				FailedCount:          &failedInvocations,
+				FunctionsInvoked:     &functionsInvoked,
+				RecordOutputChannel:  recordOutputChannel,
+				AnnounceDoneWG:       &waitForInvocations,
+				AnnounceDoneExe:      addInvocationsToGroup,
 			})
 		} else {
 			// To be used from within the Golang testing framework
@@ -243,6 +365,7 @@ func (d *Driver) functionsDriver(functionLinkedList *list.List, announceFunction
 		}
 
 		iatIndex++
+		windowIndex++
 
 		// counter updates
 		invocationSinceTheBeginningOfMinute++
@@ -373,7 +496,24 @@ func (d *Driver) internalRun() {
 
 	if d.Configuration.LoaderConfiguration.DAGMode {
 		functions := d.Configuration.Functions
-		dagLists := generator.GenerateDAGs(d.Configuration.LoaderConfiguration, functions, false)
+		var dagLists []*list.List
+		if d.workflows != nil {
+			for _, workflow := range d.workflows {
+				dagLists = append(dagLists, generator.CreateWorkflowDAG(workflow))
+			}
+		} else {
+			dagLists = generator.GenerateDAGs(d.Configuration.LoaderConfiguration, functions, false)
+		}
+		maxPayloadSize := 0
+		for _, dag := range dagLists {
+			generator.SamplePayloadSizes(dag, d.Configuration.LoaderConfiguration.Seed)
+			maxPayloadSize = max(maxPayloadSize, generator.MaxPayloadSize(dag))
+			if d.serverSideChaining {
+				validateChainedDAG(dag)
+			}
+		}
+		// the data passed between the nodes is created upfront, so that it does not count towards the invocations
+		clients.PrepareDAGPayload(maxPayloadSize, d.Configuration.LoaderConfiguration.Seed)
 		log.Infof("Starting DAG invocation driver\n")
 		for i := range len(dagLists) {
 			allIndividualDriversCompleted.Add(1)
@@ -420,6 +560,16 @@ func (d *Driver) internalRun() {
 		scraperFinishCh <- 0 // Ask the scraper to finish metrics collection
 
 		allRecordsWritten.Wait()
+
+		if d.Configuration.LoaderConfiguration.Platform == "OpenWhisk" {
+			d.writeOpenWhiskActivationsToLog()
+		}
+		if d.tenantStatistics != nil {
+			d.writeTenantStatisticsToLog()
+		}
+		if d.Configuration.LoaderConfiguration.DAGMode {
+			d.writeWorkflowRecordsToLog()
+		}
 	}
 
 	statSuccess := atomic.LoadInt64(&successfulInvocations)
@@ -433,9 +583,24 @@ func (d *Driver) internalRun() {
 }
 
 func (d *Driver) GenerateSpecification() {
+	if d.Configuration.LoaderConfiguration.StreamingSpecification {
+		d.createInvocationStreams()
+		return
+	}
+
+	if d.Configuration.LoaderConfiguration.DAGMode && d.Configuration.LoaderConfiguration.WorkflowPath != "" {
+		d.generateWorkflowSpecification()
+		return
+	}
+
 	log.Info("Generating IAT and runtime specifications for all the functions")
 
 	for i, function := range d.Configuration.Functions {
+		// IATs of traces with per-invocation arrival times are taken as they are
+		if function.Specification != nil && function.Specification.IAT != nil {
+			continue
+		}
+
 		// Equalising all the InvocationStats to the first function
 		if d.Configuration.LoaderConfiguration.DAGMode {
 			function.InvocationStats.Invocations = d.Configuration.Functions[0].InvocationStats.Invocations
@@ -451,14 +616,70 @@ func (d *Driver) GenerateSpecification() {
 	}
 }
 
-func (d *Driver) outputIATsToFile() {
-	for i, function := range d.Configuration.Functions {
-		file, _ := json.MarshalIndent(function.Specification, "", " ")
-		err := os.WriteFile("iat"+strconv.Itoa(i)+".json", file, 0644)
-		if err != nil {
-			log.Fatalf("Writing the loader config file failed: %s", err)
+// generateWorkflowSpecification generates IATs and runtime specifications of the workflows from the workflow file,
+// and leaves only the functions of their stages to be deployed
+func (d *Driver) generateWorkflowSpecification() {
+	definitions := config.ReadWorkflowConfiguration(d.Configuration.LoaderConfiguration.WorkflowPath)
+	d.workflows = generator.NewWorkflows(definitions, d.Configuration.Functions)
+
+	log.Infof("Generating IAT and runtime specifications for %d workflows", len(d.workflows))
+
+	used := make(map[*common.Function]bool)
+	for _, workflow := range d.workflows {
+		d.SpecificationGenerator.GenerateWorkflowData(
+			workflow,
+			d.Configuration.IATDistribution,
+			d.Configuration.ShiftIAT,
+			d.Configuration.TraceGranularity,
+		)
+
+		for _, stage := range workflow.Stages {
+			used[stage.Function] = true
 		}
 	}
+
+	var functions []*common.Function
+	for _, function := range d.Configuration.Functions {
+		if used[function] {
+			functions = append(functions, function)
+		}
+	}
+	d.Configuration.Functions = functions
+}
+
+// createInvocationStreams prepares lazy generation of IATs and runtime specifications, which are then produced one
+// time unit at a time while the experiment is running instead of being materialized for the whole trace upfront
+func (d *Driver) createInvocationStreams() {
+	if d.Configuration.LoaderConfiguration.DAGMode {
+		log.Fatal("Streaming specification generation is not supported in DAG mode.")
+	}
+
+	log.Info("Generating IAT and runtime specifications lazily during the experiment")
+
+	d.invocationStreams = make(map[string]*generator.InvocationStream)
+	for _, function := range d.Configuration.Functions {
+		// IATs of traces with per-invocation arrival times are taken as they are
+		if function.Specification != nil && function.Specification.IAT != nil {
+			continue
+		}
+
+		d.invocationStreams[function.Name] = d.SpecificationGenerator.NewInvocationStream(
+			function,
+			d.Configuration.IATDistribution,
+			d.Configuration.ShiftIAT,
+			d.Configuration.TraceGranularity,
+		)
+
+		function.Specification = &common.FunctionSpecification{}
+	}
+}
+
+func (d *Driver) specificationPath() string {
+	if d.Configuration.LoaderConfiguration.SpecificationPath == "" {
+		return generator.DefaultSpecificationPath
+	}
+
+	return d.Configuration.LoaderConfiguration.SpecificationPath
 }
 
 func (d *Driver) ReadOrWriteFileSpecification(writeIATsToFile bool, readIATsFromFile bool) {
@@ -466,24 +687,26 @@ func (d *Driver) ReadOrWriteFileSpecification(writeIATsToFile bool, readIATsFrom
 		log.Fatal("Invalid loader configuration. No point to read and write IATs within the same run.")
 	}
 
+	if (writeIATsToFile || readIATsFromFile) && d.invocationStreams != nil {
+		log.Fatal("Invalid loader configuration. IATs cannot be read from or written to a file with streaming specification generation.")
+	}
+
+	if (writeIATsToFile || readIATsFromFile) && d.workflows != nil {
+		log.Fatal("Invalid loader configuration. IATs cannot be read from or written to a file for workflows.")
+	}
+
 	if writeIATsToFile {
-		d.outputIATsToFile()
+		if err := generator.WriteSpecificationBundle(d.specificationPath(), d.Configuration); err != nil {
+			log.Fatalf("Writing the specification bundle to %s failed: %s", d.specificationPath(), err)
+		}
 
-		log.Info("IATs have been generated. The program has exited.")
+		log.Infof("IATs have been generated into %s. The program has exited.", d.specificationPath())
 		os.Exit(0)
 	}
 
 	if readIATsFromFile {
-		for i := range d.Configuration.Functions {
-			var spec common.FunctionSpecification
-
-			iatFile, _ := os.ReadFile("iat" + strconv.Itoa(i) + ".json")
-			err := json.Unmarshal(iatFile, &spec)
-			if err != nil {
-				log.Fatalf("Failed to unmarshal IAT file: %s", err)
-			}
-
-			d.Configuration.Functions[i].Specification = &spec
+		if err := generator.ReadSpecificationBundle(d.specificationPath(), d.Configuration); err != nil {
+			log.Fatalf("Failed to read the specification bundle from %s: %s", d.specificationPath(), err)
 		}
 	}
 }
@@ -495,6 +718,11 @@ func (d *Driver) RunExperiment() {
 
 	trace.ApplyResourceLimits(d.Configuration.Functions, d.Configuration.LoaderConfiguration.CPULimit)
 
+	if d.Configuration.LoaderConfiguration.MultiTenant {
+		d.Configuration.Tenants = trace.AssignTenants(d.Configuration.Functions, d.Configuration.LoaderConfiguration.Tenants, d.Configuration.LoaderConfiguration.DefaultTenantQuota)
+		d.tenantStatistics = newTenantStatistics(d.Configuration.Tenants, d.Configuration.Functions)
+	}
+
 	deployer := deployment.CreateDeployer(d.Configuration)
 	deployer.Deploy(d.Configuration)
 
@@ -29,6 +29,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
@@ -38,6 +39,7 @@ import (
 	"github.com/gocarina/gocsv"
 	"github.com/sirupsen/logrus"
 	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/generator"
 	"github.com/vhive-serverless/loader/pkg/metric"
 	"github.com/vhive-serverless/loader/pkg/workload/standard"
 )
@@ -53,8 +55,9 @@ func createFakeLoaderConfiguration() *config.LoaderConfiguration {
 	}
 }
 
-func createTestDriver(invocationStats []int) *Driver {
+func createTestDriver(t *testing.T, invocationStats []int) *Driver {
 	cfg := createFakeLoaderConfiguration()
+	cfg.OutputPathPrefix = filepath.Join(t.TempDir(), "test")
 
 	driver := NewDriver(&config.Configuration{
 		LoaderConfiguration: cfg,
@@ -129,7 +132,7 @@ func TestInvokeFunctionFromDriver(t *testing.T) {
 			invocationRecordOutputChannel := make(chan *metric.ExecutionRecord, 1)
 			announceDone := &sync.WaitGroup{}
 
-			testDriver := createTestDriver([]int{1})
+			testDriver := createTestDriver(t, []int{1})
 			var functionsInvoked int64
 			if !test.forceFail {
 				address, port := "localhost", test.port
@@ -194,7 +197,7 @@ func TestDAGInvocation(t *testing.T) {
 	invocationRecordOutputChannel := make(chan *metric.ExecutionRecord, functionsToInvoke)
 	announceDone := &sync.WaitGroup{}
 
-	testDriver := createTestDriver([]int{4})
+	testDriver := createTestDriver(t, []int{4})
 	address, port := "localhost", 8085
 	function := testDriver.Configuration.Functions[0]
 	function.Endpoint = fmt.Sprintf("%s:%d", address, port)
@@ -257,8 +260,203 @@ func TestDAGInvocation(t *testing.T) {
 	}
 }
 
+func TestDAGJoinInvocation(t *testing.T) {
+	address, port := "localhost", 8086
+	go standard.StartGRPCServer(address, port, standard.TraceFunction, "")
+	time.Sleep(2 * time.Second)
+
+	tests := []struct {
+		testName        string
+		policy          common.JoinPolicy
+		failBranch      bool
+		expectedSuccess int64
+		// size of the data passed to the join by its predecessors, zero if it is not invoked
+		expectedPayload int
+	}{
+		{testName: "all_succeed", policy: common.JoinFail, expectedSuccess: 5, expectedPayload: 200},
+		{testName: "fail", policy: common.JoinFail, failBranch: true, expectedSuccess: 2},
+		{testName: "skip", policy: common.JoinSkip, failBranch: true, expectedSuccess: 3},
+		{testName: "partial", policy: common.JoinPartial, failBranch: true, expectedSuccess: 4, expectedPayload: 100},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			var successCount, failureCount, functionsInvoked int64
+			invocationRecordOutputChannel := make(chan *metric.ExecutionRecord, 5)
+			announceDone := &sync.WaitGroup{}
+
+			testDriver := createTestDriver(t, []int{1})
+			testDriver.Configuration.LoaderConfiguration.DAGMode = true
+			testDriver.joinPolicy = test.policy
+
+			function := testDriver.Configuration.Functions[0]
+			function.Endpoint = fmt.Sprintf("%s:%d", address, port)
+			function.Specification.RuntimeSpecification = []common.RuntimeSpecification{{
+				Runtime: 10,
+				Memory:  128,
+			}}
+			failingFunction := *function
+			if test.failBranch {
+				failingFunction.Endpoint = ""
+			}
+
+			// root -> failing and root -> other are joined by join -> last, where each predecessor passes 100 bytes
+			payload := &common.PayloadSize{Distribution: common.ConstantPayload, MeanBytes: 100}
+			joinBranch := list.New()
+			joinBranch.PushBack(&common.Node{Function: function, Depth: 2, Predecessors: 2, Stage: "join", Payload: payload})
+			joinBranch.PushBack(&common.Node{Function: function, Depth: 3, Predecessors: 1})
+
+			otherBranch := list.New()
+			otherBranch.PushBack(&common.Node{Function: function, Depth: 1, Branches: []*list.List{joinBranch}})
+
+			rootFunction := list.New()
+			rootFunction.PushBack(&common.Node{Function: function, Depth: 0, Branches: []*list.List{otherBranch}})
+			rootFunction.PushBack(&common.Node{Function: &failingFunction, Depth: 1, Branches: []*list.List{joinBranch}})
+			generator.SamplePayloadSizes(rootFunction, 42)
+
+			metadata := &InvocationMetadata{
+				RootFunction:        rootFunction,
+				Phase:               common.ExecutionPhase,
+				IatIndex:            0,
+				InvocationID:        composeInvocationID(common.MinuteGranularity, 0, 0),
+				SuccessCount:        &successCount,
+				FailedCount:         &failureCount,
+				FunctionsInvoked:    &functionsInvoked,
+				RecordOutputChannel: invocationRecordOutputChannel,
+				AnnounceDoneWG:      announceDone,
+			}
+
+			announceDone.Add(1)
+			testDriver.invokeFunction(metadata)
+			announceDone.Wait()
+
+			expectedFailures := int64(0)
+			if test.failBranch {
+				expectedFailures = 1
+			}
+			if successCount != test.expectedSuccess || failureCount != expectedFailures {
+				t.Errorf("Expected %d successful and %d failed invocations, got %d and %d.",
+					test.expectedSuccess, expectedFailures, successCount, failureCount)
+			}
+
+			if testDriver.workflowRecords.Length() != 1 {
+				t.Fatalf("Expected a single workflow record, got %d.", testDriver.workflowRecords.Length())
+			}
+			record := testDriver.workflowRecords.Dequeue()
+			if record.NodesExecuted != int(successCount+failureCount) || record.FailedNodes != int(failureCount) ||
+				record.Latency <= 0 || record.CriticalPathLength <= 0 {
+
+				t.Errorf("Unexpected workflow record %+v.", record)
+			}
+
+			joinPayload := 0
+			for len(invocationRecordOutputChannel) > 0 {
+				if record := <-invocationRecordOutputChannel; record.Stage == "join" {
+					joinPayload = record.PayloadBytes
+				}
+			}
+			if joinPayload != test.expectedPayload {
+				t.Errorf("Expected the join to receive %d bytes, got %d.", test.expectedPayload, joinPayload)
+			}
+		})
+	}
+}
+
+func TestDAGServerSideChaining(t *testing.T) {
+	address, port := "localhost", 8087
+	go standard.StartGRPCServer(address, port, standard.TraceFunction, "")
+	time.Sleep(2 * time.Second)
+
+	tests := []struct {
+		testName        string
+		failStage       bool
+		expectedSuccess int64
+		expectedFailure int64
+	}{
+		{testName: "all_succeed", expectedSuccess: 4},
+		// the last stage after the failed one is abandoned
+		{testName: "stage_fails", failStage: true, expectedSuccess: 2, expectedFailure: 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			var successCount, failureCount, functionsInvoked int64
+			invocationRecordOutputChannel := make(chan *metric.ExecutionRecord, 4)
+			announceDone := &sync.WaitGroup{}
+
+			testDriver := createTestDriver(t, []int{1})
+			testDriver.Configuration.LoaderConfiguration.DAGMode = true
+			testDriver.serverSideChaining = true
+
+			function := testDriver.Configuration.Functions[0]
+			function.Endpoint = fmt.Sprintf("%s:%d", address, port)
+			function.Specification.RuntimeSpecification = []common.RuntimeSpecification{{
+				Runtime: 10,
+				Memory:  128,
+			}}
+			failingFunction := *function
+			if test.failStage {
+				failingFunction.Endpoint = fmt.Sprintf("%s:%d", address, port+1000)
+			}
+
+			// root -> middle -> last and root -> side, where the payload is passed from middle to last
+			sideBranch := list.New()
+			sideBranch.PushBack(&common.Node{Function: function, Depth: 1, Stage: "side"})
+
+			rootFunction := list.New()
+			rootFunction.PushBack(&common.Node{Function: function, Depth: 0, Stage: "root", Branches: []*list.List{sideBranch}})
+			rootFunction.PushBack(&common.Node{Function: &failingFunction, Depth: 1, Stage: "middle"})
+			rootFunction.PushBack(&common.Node{Function: function, Depth: 2, Stage: "last",
+				Payload: &common.PayloadSize{Distribution: common.ConstantPayload, MeanBytes: 100}})
+			generator.SamplePayloadSizes(rootFunction, 42)
+
+			metadata := &InvocationMetadata{
+				RootFunction:        rootFunction,
+				Phase:               common.ExecutionPhase,
+				IatIndex:            0,
+				InvocationID:        composeInvocationID(common.MinuteGranularity, 0, 0),
+				SuccessCount:        &successCount,
+				FailedCount:         &failureCount,
+				FunctionsInvoked:    &functionsInvoked,
+				RecordOutputChannel: invocationRecordOutputChannel,
+				AnnounceDoneWG:      announceDone,
+			}
+
+			announceDone.Add(1)
+			testDriver.invokeFunction(metadata)
+			announceDone.Wait()
+
+			if successCount != test.expectedSuccess || failureCount != test.expectedFailure {
+				t.Errorf("Expected %d successful and %d failed invocations, got %d and %d.",
+					test.expectedSuccess, test.expectedFailure, successCount, failureCount)
+			}
+
+			parents := map[string]string{}
+			for len(invocationRecordOutputChannel) > 0 {
+				record := <-invocationRecordOutputChannel
+				parents[record.Stage] = record.ParentStage
+
+				if record.Stage == "last" && record.PayloadBytes != 100 {
+					t.Errorf("Expected the last stage to receive 100 bytes, got %d.", record.PayloadBytes)
+				}
+			}
+			if parents["middle"] != "root" || parents["side"] != "root" {
+				t.Errorf("Unexpected parents of the stages %v.", parents)
+			}
+			if _, ok := parents["last"]; ok == test.failStage {
+				t.Errorf("Unexpected invocation of the last stage %v.", parents)
+			}
+
+			record := testDriver.workflowRecords.Dequeue()
+			if record == nil || record.NodesExecuted != len(parents) || record.FailedNodes != int(failureCount) {
+				t.Errorf("Unexpected workflow record %+v.", record)
+			}
+		})
+	}
+}
+
 func TestGlobalMetricsCollector(t *testing.T) {
-	driver := createTestDriver([]int{5})
+	driver := createTestDriver(t, []int{5})
 
 	inputChannel := make(chan *metric.ExecutionRecord)
 	totalIssuedChannel := make(chan int64)
@@ -334,7 +532,7 @@ func TestDriverBackgroundProcesses(t *testing.T) {
 				t.Skip("Not yet implemented")
 			}
 
-			driver := createTestDriver([]int{5})
+			driver := createTestDriver(t, []int{5})
 			globalCollectorAnnounceDone := &sync.WaitGroup{}
 
 			completed, _, _, _ := driver.startBackgroundProcesses(globalCollectorAnnounceDone)
@@ -349,6 +547,7 @@ func TestDriverCompletely(t *testing.T) {
 		testName              string
 		experimentDurationMin int
 		withWarmup            bool
+		streaming             bool
 		traceGranularity      common.TraceGranularity
 		invocationStats       []int
 		expectedInvocations   int
@@ -404,6 +603,15 @@ func TestDriverCompletely(t *testing.T) {
 			invocationStats:       []int{0, 5},
 			expectedInvocations:   5,
 		},
+		{
+			testName:              "streaming_with_warmup",
+			experimentDurationMin: 2,
+			invocationStats:       []int{5, 5},
+			traceGranularity:      common.MinuteGranularity,
+			withWarmup:            true,
+			streaming:             true,
+			expectedInvocations:   10,
+		},
 	}
 
 	for _, test := range tests {
@@ -411,7 +619,7 @@ func TestDriverCompletely(t *testing.T) {
 			logrus.SetLevel(logrus.DebugLevel)
 			logrus.SetFormatter(&logrus.TextFormatter{TimestampFormat: time.StampMilli, FullTimestamp: true})
 
-			driver := createTestDriver(test.invocationStats)
+			driver := createTestDriver(t, test.invocationStats)
 
 			if test.withWarmup {
 				if test.traceGranularity == common.MinuteGranularity {
@@ -422,6 +630,7 @@ func TestDriverCompletely(t *testing.T) {
 			}
 			driver.Configuration.TraceDuration = test.experimentDurationMin
 			driver.Configuration.TraceGranularity = test.traceGranularity
+			driver.Configuration.LoaderConfiguration.StreamingSpecification = test.streaming
 
 			driver.GenerateSpecification()
 			driver.RunExperiment()
@@ -449,7 +658,7 @@ func TestDriverCompletely(t *testing.T) {
 
 				if test.withWarmup {
 					threshold := 60
-					if test.testName == "with_warmup" {
+					if test.testName == "with_warmup" || test.testName == "streaming_with_warmup" {
 						threshold = 5
 					}
 
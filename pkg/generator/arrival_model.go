@@ -0,0 +1,163 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+type ArrivalModelType string
+
+const (
+	// DistributionArrivals follows the configured IATDistribution
+	DistributionArrivals ArrivalModelType = "distribution"
+	// PeriodicArrivals fires at equidistant instants aligned to the beginning of each time unit, like timer triggers do
+	PeriodicArrivals ArrivalModelType = "periodic"
+	// BatchArrivals groups invocations into batches arriving according to the configured IATDistribution, like
+	// consumers of queues and event streams do
+	BatchArrivals ArrivalModelType = "batch"
+	// MMPPArrivals follows a Markov-modulated Poisson process, which alternates between states of different arrival
+	// rates to generate bursts within a time unit
+	MMPPArrivals ArrivalModelType = "mmpp"
+)
+
+// ArrivalModel determines how the invocations of a function are placed within a time unit of the trace
+type ArrivalModel struct {
+	Type ArrivalModelType
+	// maximum number of invocations in a batch
+	BatchSize int
+	// time between invocations of the same batch in μs
+	BatchInterval float64
+	// MMPP parameters, fitted to the invocations of each function if nil
+	MMPP *MMPP
+}
+
+// NewArrivalModels creates the arrival models per trigger from the configuration. Triggers without an arrival model
+// follow the configured IATDistribution.
+func NewArrivalModels(models map[string]config.ArrivalModelConfiguration) map[string]ArrivalModel {
+	result := make(map[string]ArrivalModel)
+
+	for trigger, configuration := range models {
+		model := ArrivalModel{
+			Type:          ArrivalModelType(strings.ToLower(configuration.Model)),
+			BatchSize:     configuration.BatchSize,
+			BatchInterval: configuration.BatchIntervalMs * 1000,
+		}
+		if len(configuration.MMPPRates) > 0 || len(configuration.MMPPTransitions) > 0 {
+			model.MMPP = &MMPP{Rates: configuration.MMPPRates, Transitions: configuration.MMPPTransitions}
+		}
+
+		switch model.Type {
+		case DistributionArrivals, PeriodicArrivals:
+		case BatchArrivals:
+			if model.BatchSize < 1 {
+				log.Fatalf("Batch size of the arrival model of trigger %s has to be positive.", trigger)
+			}
+			if model.BatchInterval < 0 {
+				log.Fatalf("Batch interval of the arrival model of trigger %s cannot be negative.", trigger)
+			}
+		case MMPPArrivals:
+			if model.MMPP != nil {
+				model.MMPP.validate("trigger " + trigger)
+			}
+		default:
+			log.Fatalf("Unsupported arrival model %s of trigger %s.", configuration.Model, trigger)
+		}
+
+		result[strings.ToLower(trigger)] = model
+	}
+
+	return result
+}
+
+// SetArrivalModels sets the arrival models per trigger used for generating IATs
+func (s *SpecificationGenerator) SetArrivalModels(models map[string]ArrivalModel) {
+	s.arrivalModels = models
+}
+
+// arrivalModel returns the arrival model of the function, where the MMPP is fitted to the function if not configured
+func (s *SpecificationGenerator) arrivalModel(function *common.Function, granularity common.TraceGranularity) ArrivalModel {
+	if function.InvocationStats != nil {
+		if model, ok := s.arrivalModels[strings.ToLower(function.InvocationStats.Trigger)]; ok {
+			if model.Type == MMPPArrivals && model.MMPP == nil {
+				stats := function.InvocationStats
+				model.MMPP = FitMMPP(stats.InvocationWindow(0, stats.TimeUnits()), getBlankTimeUnit(granularity))
+			}
+
+			return model
+		}
+	}
+
+	return ArrivalModel{Type: DistributionArrivals}
+}
+
+// generateIATPerTimeUnit generates IAT for one time unit according to the arrival model
+func (s *SpecificationGenerator) generateIATPerTimeUnit(numberOfInvocations int, model ArrivalModel, iatDistribution common.IatDistribution, shiftIAT bool, granularity common.TraceGranularity) ([]float64, float64) {
+	switch model.Type {
+	case PeriodicArrivals:
+		return s.generateIATPerGranularity(numberOfInvocations, common.Equidistant, false, granularity)
+	case BatchArrivals:
+		return s.generateBatchIAT(numberOfInvocations, model, iatDistribution, shiftIAT, granularity)
+	case MMPPArrivals:
+		return s.generateMMPPIAT(numberOfInvocations, model.MMPP, granularity)
+	default:
+		return s.generateIATPerGranularity(numberOfInvocations, iatDistribution, shiftIAT, granularity)
+	}
+}
+
+// generateBatchIAT generates arrivals of batches according to the IAT distribution, where the invocations of a batch
+// are BatchInterval apart. The interval is shortened if the batch would otherwise overlap with the next one.
+func (s *SpecificationGenerator) generateBatchIAT(numberOfInvocations int, model ArrivalModel, iatDistribution common.IatDistribution, shiftIAT bool, granularity common.TraceGranularity) ([]float64, float64) {
+	batchSize := common.MaxOf(model.BatchSize, 1)
+	numberOfBatches := (numberOfInvocations + batchSize - 1) / batchSize
+
+	batchIAT, duration := s.generateIATPerGranularity(numberOfBatches, iatDistribution, shiftIAT, granularity)
+	if numberOfBatches == 0 || batchSize == 1 {
+		return batchIAT, duration
+	}
+
+	// batchIAT holds the offset of the first batch, the IATs between the batches and the time after the last one
+	iatResult := []float64{batchIAT[0]}
+	for batch := 0; batch < numberOfBatches; batch++ {
+		size := common.MinOf(batchSize, numberOfInvocations-batch*batchSize)
+		gap := batchIAT[batch+1]
+
+		interval := model.BatchInterval
+		if size > 1 && interval*float64(size) > gap {
+			interval = gap / float64(size)
+		}
+
+		for i := 1; i < size; i++ {
+			iatResult = append(iatResult, interval)
+		}
+		iatResult = append(iatResult, gap-interval*float64(size-1))
+	}
+
+	return iatResult, duration
+}
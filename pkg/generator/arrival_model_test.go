@@ -0,0 +1,147 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+func createTriggeredFunction(trigger string, invocations []int) *common.Function {
+	function := testFunction
+	function.InvocationStats = &common.FunctionInvocationStats{
+		Trigger:     trigger,
+		Invocations: invocations,
+	}
+
+	return &function
+}
+
+func TestTriggerArrivalModels(t *testing.T) {
+	tests := []struct {
+		testName    string
+		trigger     string
+		models      map[string]config.ArrivalModelConfiguration
+		invocations []int
+		expectedIAT common.IATArray
+	}{
+		{
+			testName:    "timer_periodic",
+			trigger:     "timer",
+			models:      map[string]config.ArrivalModelConfiguration{"timer": {Model: "periodic"}},
+			invocations: []int{2, 0, 3},
+			expectedIAT: common.IATArray{0, 30_000_000, 90_000_000, 20_000_000, 20_000_000},
+		},
+		{
+			testName:    "http_override_periodic",
+			trigger:     "HTTP",
+			models:      map[string]config.ArrivalModelConfiguration{"http": {Model: "periodic"}},
+			invocations: []int{4},
+			expectedIAT: common.IATArray{0, 15_000_000, 15_000_000, 15_000_000},
+		},
+		{
+			testName:    "queue_batch",
+			trigger:     "queue",
+			models:      map[string]config.ArrivalModelConfiguration{"queue": {Model: "batch", BatchSize: 2, BatchIntervalMs: 1}},
+			invocations: []int{5},
+			expectedIAT: common.IATArray{0, 1_000, 19_999_000, 1_000, 19_999_000},
+		},
+		{
+			testName:    "event_batch_shortened_interval",
+			trigger:     "event",
+			models:      map[string]config.ArrivalModelConfiguration{"event": {Model: "batch", BatchSize: 3, BatchIntervalMs: 60_000}},
+			invocations: []int{3, 1},
+			expectedIAT: common.IATArray{0, 20_000_000, 20_000_000, 20_000_000},
+		},
+		{
+			testName:    "storage_simultaneous_batch",
+			trigger:     "storage",
+			models:      map[string]config.ArrivalModelConfiguration{"storage": {Model: "batch", BatchSize: 4}},
+			invocations: []int{4},
+			expectedIAT: common.IATArray{0, 0, 0, 0},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			sg := NewSpecificationGenerator(42)
+			sg.SetArrivalModels(NewArrivalModels(test.models))
+
+			// the IAT distribution of the configuration applies to batches only, so equidistant keeps the test exact
+			spec := sg.GenerateInvocationData(createTriggeredFunction(test.trigger, test.invocations), common.Equidistant, false, common.MinuteGranularity)
+
+			if len(spec.IAT) != len(test.expectedIAT) {
+				t.Fatalf("Expected IATs %v, got %v.", test.expectedIAT, spec.IAT)
+			}
+			for i := range spec.IAT {
+				if math.Abs(spec.IAT[i]-test.expectedIAT[i]) > 1e-6 {
+					t.Fatalf("Expected IATs %v, got %v.", test.expectedIAT, spec.IAT)
+				}
+			}
+		})
+	}
+}
+
+func TestTriggersFollowDistributionByDefault(t *testing.T) {
+	invocations := []int{5, 0, 17, 3}
+
+	distribution := NewSpecificationGenerator(42).GenerateInvocationData(createTriggeredFunction("http", invocations), common.Exponential, true, common.MinuteGranularity)
+
+	// without configured arrival models, the trigger of a function does not change its IATs
+	for _, trigger := range []string{"timer", "queue", "event", "storage"} {
+		spec := NewSpecificationGenerator(42).GenerateInvocationData(createTriggeredFunction(trigger, invocations), common.Exponential, true, common.MinuteGranularity)
+		if !reflect.DeepEqual(spec.IAT, distribution.IAT) {
+			t.Errorf("Function with trigger %s should follow the IAT distribution.", trigger)
+		}
+	}
+}
+
+func TestTimerIgnoresIATDistribution(t *testing.T) {
+	sg := NewSpecificationGenerator(42)
+	sg.SetArrivalModels(NewArrivalModels(map[string]config.ArrivalModelConfiguration{"timer": {Model: "periodic"}}))
+
+	spec := sg.GenerateInvocationData(createTriggeredFunction("timer", []int{1, 1, 1}), common.Exponential, true, common.MinuteGranularity)
+	if !reflect.DeepEqual(spec.IAT, common.IATArray{0, 60_000_000, 60_000_000}) {
+		t.Errorf("Timer trigger should fire at the beginning of each minute, got %v.", spec.IAT)
+	}
+}
+
+func TestBatchOfOneMatchesDistribution(t *testing.T) {
+	invocations := []int{5, 0, 17, 3}
+
+	sg := NewSpecificationGenerator(42)
+	sg.SetArrivalModels(NewArrivalModels(map[string]config.ArrivalModelConfiguration{"queue": {Model: "batch", BatchSize: 1}}))
+
+	batch := sg.GenerateInvocationData(createTriggeredFunction("queue", invocations), common.Exponential, true, common.MinuteGranularity)
+	distribution := NewSpecificationGenerator(42).GenerateInvocationData(createTriggeredFunction("http", invocations), common.Exponential, true, common.MinuteGranularity)
+
+	if !reflect.DeepEqual(batch.IAT, distribution.IAT) {
+		t.Error("Batches of a single invocation should follow the IAT distribution.")
+	}
+}
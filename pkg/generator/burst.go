@@ -0,0 +1,200 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+// BurstEvent multiplies the arrival rate of a fraction of functions during a period of the experiment, so that the
+// functions surge together
+type BurstEvent struct {
+	// beginning and length of the burst in μs from the beginning of the experiment
+	Start    float64
+	Duration float64
+	// time between repetitions of the burst in μs, zero for a single burst
+	Period float64
+	// factor by which the arrival rate is multiplied during the burst
+	Amplitude float64
+	// fraction of functions taking part in the burst
+	FunctionFraction float64
+	// whether the burst adds invocations to the trace instead of moving the invocations of the time unit into it
+	ScaleCounts bool
+}
+
+// NewBurstEvents converts burst events of the configuration, given in seconds, and validates them
+func NewBurstEvents(events []config.BurstEventConfiguration) []BurstEvent {
+	var result []BurstEvent
+
+	for i, event := range events {
+		if event.DurationSeconds <= 0 || event.Amplitude <= 0 || event.StartSecond < 0 || event.PeriodSeconds < 0 {
+			log.Fatalf("Burst event %d needs a positive duration and amplitude and a non-negative start and period.", i)
+		}
+		if event.FunctionFraction <= 0 || event.FunctionFraction > 1 {
+			log.Fatalf("Function fraction of burst event %d has to be in (0, 1].", i)
+		}
+
+		result = append(result, BurstEvent{
+			Start:            event.StartSecond * common.OneSecondInMicroseconds,
+			Duration:         event.DurationSeconds * common.OneSecondInMicroseconds,
+			Period:           event.PeriodSeconds * common.OneSecondInMicroseconds,
+			Amplitude:        event.Amplitude,
+			FunctionFraction: event.FunctionFraction,
+			ScaleCounts:      event.ScaleCounts,
+		})
+	}
+
+	return result
+}
+
+// SetBurstEvents sets the burst events shared by all functions
+func (s *SpecificationGenerator) SetBurstEvents(events []BurstEvent) {
+	s.burstEvents = events
+}
+
+// selectBurstEvents selects the burst events the function with the given identity takes part in. The selection depends
+// only on the seed and the trace row of the function, so the same functions burst together regardless of the order of
+// functions and the names the parser gives them.
+func (s *SpecificationGenerator) selectBurstEvents(identity string) {
+	s.functionBursts = nil
+
+	for i, event := range s.burstEvents {
+		hash := fnv.New64a()
+		_, _ = hash.Write([]byte(fmt.Sprintf("%d/%s/%d", s.seed, identity, i)))
+
+		if float64(hash.Sum64())/float64(math.MaxUint64) < event.FunctionFraction {
+			s.functionBursts = append(s.functionBursts, event)
+		}
+	}
+}
+
+// burstSegments returns the relative arrival rate during the time unit with the given index, where the rates of
+// overlapping bursts multiply. With onlyScaling, only the bursts that add invocations are considered.
+func (s *SpecificationGenerator) burstSegments(timeUnit int, granularity common.TraceGranularity, onlyScaling bool) []rateSegment {
+	length := getBlankTimeUnit(granularity)
+	begin := float64(timeUnit) * length
+
+	type boundary struct {
+		time      float64
+		amplitude float64
+	}
+	var boundaries []boundary
+
+	for _, event := range s.functionBursts {
+		if onlyScaling && !event.ScaleCounts {
+			continue
+		}
+
+		start := event.Start
+		if event.Period > 0 && begin > event.Start+event.Duration {
+			// skip the repetitions that end before the time unit
+			start += math.Floor((begin-event.Start-event.Duration)/event.Period) * event.Period
+		}
+
+		for ; start < begin+length; start += event.Period {
+			from, to := math.Max(start, begin)-begin, math.Min(start+event.Duration, begin+length)-begin
+			if from < to {
+				boundaries = append(boundaries, boundary{from, event.Amplitude}, boundary{to, 1 / event.Amplitude})
+			}
+
+			if event.Period == 0 {
+				break
+			}
+		}
+	}
+
+	sort.SliceStable(boundaries, func(i, j int) bool { return boundaries[i].time < boundaries[j].time })
+
+	var segments []rateSegment
+	t, rate := 0.0, 1.0
+	for _, b := range boundaries {
+		if b.time > t {
+			segments = append(segments, rateSegment{start: t, end: b.time, rate: rate})
+			t = b.time
+		}
+		rate *= b.amplitude
+	}
+
+	return append(segments, rateSegment{start: t, end: length, rate: rate})
+}
+
+// burstInvocations returns the number of invocations of the time unit, which is multiplied by the average rate of the
+// bursts that scale the counts
+func (s *SpecificationGenerator) burstInvocations(timeUnit int, numberOfInvocations int, granularity common.TraceGranularity) int {
+	if len(s.functionBursts) == 0 || numberOfInvocations == 0 {
+		return numberOfInvocations
+	}
+
+	cumulative := cumulativeRates(s.burstSegments(timeUnit, granularity, true))
+	averageRate := cumulative[len(cumulative)-1] / getBlankTimeUnit(granularity)
+
+	return int(math.Round(float64(numberOfInvocations) * averageRate))
+}
+
+// applyBursts moves the invocations of the time unit in time, so that they arrive at the rate of the bursts relative
+// to the rest of the time unit, while keeping their number and order
+func (s *SpecificationGenerator) applyBursts(timeUnit int, iat []float64, granularity common.TraceGranularity) []float64 {
+	if len(s.functionBursts) == 0 || len(iat) < 2 {
+		return iat
+	}
+
+	segments := s.burstSegments(timeUnit, granularity, false)
+	if len(segments) == 1 {
+		return iat
+	}
+
+	length := getBlankTimeUnit(granularity)
+	cumulative := cumulativeRates(segments)
+	total := cumulative[len(cumulative)-1]
+
+	result := make([]float64, len(iat))
+	timestamp, previous := 0.0, 0.0
+	for i := 0; i < len(iat)-1; i++ {
+		timestamp += iat[i]
+
+		// without bursts, the cumulative rate grows uniformly over the time unit
+		moved := timeOfCumulativeRate(segments, cumulative, math.Min(timestamp/length, 1)*total*(1-1e-12))
+		result[i] = moved - previous
+		previous = moved
+	}
+	result[len(result)-1] = length - previous
+
+	return result
+}
+
+// generateBurstyIATPerTimeUnit generates IATs for the time unit with the given index according to the arrival model
+// and applies the burst events of the function
+func (s *SpecificationGenerator) generateBurstyIATPerTimeUnit(timeUnit int, numberOfInvocations int, model ArrivalModel, iatDistribution common.IatDistribution, shiftIAT bool, granularity common.TraceGranularity) ([]float64, float64) {
+	iat, duration := s.generateIATPerTimeUnit(s.burstInvocations(timeUnit, numberOfInvocations, granularity), model, iatDistribution, shiftIAT, granularity)
+
+	return s.applyBursts(timeUnit, iat, granularity), duration
+}
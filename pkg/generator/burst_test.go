@@ -0,0 +1,221 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+func countInWindow(iat common.IATArray, from, to float64) int {
+	count := 0
+
+	timestamp := 0.0
+	for _, value := range iat {
+		timestamp += value
+		if timestamp >= from && timestamp < to {
+			count++
+		}
+	}
+
+	return count
+}
+
+func TestBurstEvents(t *testing.T) {
+	tests := []struct {
+		testName       string
+		event          config.BurstEventConfiguration
+		expectedCount  []int
+		expectedBursts []int
+	}{
+		{
+			testName:       "preserve_counts",
+			event:          config.BurstEventConfiguration{StartSecond: 10, DurationSeconds: 10, Amplitude: 5, FunctionFraction: 1},
+			expectedCount:  []int{60, 60},
+			expectedBursts: []int{30, 10},
+		},
+		{
+			testName:       "scale_counts",
+			event:          config.BurstEventConfiguration{StartSecond: 10, DurationSeconds: 10, Amplitude: 5, FunctionFraction: 1, ScaleCounts: true},
+			expectedCount:  []int{100, 60},
+			expectedBursts: []int{50, 10},
+		},
+		{
+			testName:       "periodic",
+			event:          config.BurstEventConfiguration{StartSecond: 10, DurationSeconds: 10, PeriodSeconds: 60, Amplitude: 5, FunctionFraction: 1},
+			expectedCount:  []int{60, 60},
+			expectedBursts: []int{30, 30},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			sg := NewSpecificationGenerator(42)
+			sg.SetBurstEvents(NewBurstEvents([]config.BurstEventConfiguration{test.event}))
+
+			function := createTriggeredFunction("http", []int{60, 60})
+			spec := sg.GenerateInvocationData(function, common.Equidistant, false, common.MinuteGranularity)
+
+			if !reflect.DeepEqual(spec.PerMinuteCount, test.expectedCount) {
+				t.Errorf("Expected per-minute count %v, got %v.", test.expectedCount, spec.PerMinuteCount)
+			}
+			if len(spec.IAT) != test.expectedCount[0]+test.expectedCount[1] {
+				t.Errorf("Expected %d IATs, got %d.", test.expectedCount[0]+test.expectedCount[1], len(spec.IAT))
+			}
+
+			for minute, expected := range test.expectedBursts {
+				begin := float64(minute) * 60_000_000
+				if count := countInWindow(spec.IAT, begin+10_000_000, begin+20_000_000); math.Abs(float64(count-expected)) > 1 {
+					t.Errorf("Expected %d invocations in the burst of minute %d, got %d.", expected, minute, count)
+				}
+			}
+
+			// streams generate the same counts
+			streamGenerator := NewSpecificationGenerator(42)
+			streamGenerator.SetBurstEvents(NewBurstEvents([]config.BurstEventConfiguration{test.event}))
+			stream := streamGenerator.NewInvocationStream(function, common.Equidistant, false, common.MinuteGranularity)
+
+			count := make([]int, len(test.expectedCount))
+			for iat, _, ok := stream.Next(); ok; iat, _, ok = stream.Next() {
+				count[stream.TimeUnit()] += len(iat)
+			}
+			if !reflect.DeepEqual(count, test.expectedCount) {
+				t.Errorf("Expected per-minute count of the stream %v, got %v.", test.expectedCount, count)
+			}
+		})
+	}
+}
+
+func TestBurstFunctionSelection(t *testing.T) {
+	events := NewBurstEvents([]config.BurstEventConfiguration{{DurationSeconds: 1, Amplitude: 2, FunctionFraction: 0.3}})
+
+	var forward, backward []*common.Function
+	for i := 0; i < 1000; i++ {
+		function := &common.Function{
+			Name:            fmt.Sprintf("function-%d", i),
+			InvocationStats: &common.FunctionInvocationStats{HashFunction: fmt.Sprintf("hash-%d", i)},
+		}
+
+		forward = append(forward, function)
+		backward = append([]*common.Function{function}, backward...)
+	}
+
+	selected := make(map[string]bool)
+	differentSeed := 0
+	for run, functions := range [][]*common.Function{forward, backward, forward} {
+		// the last run uses another seed
+		sg := NewSpecificationGenerator(int64(run / 2))
+		sg.SetBurstEvents(events)
+
+		count := 0
+		for _, function := range functions {
+			sg.selectBurstEvents(functionIdentity(function))
+
+			inBurst := len(sg.functionBursts) == 1
+			hash := function.InvocationStats.HashFunction
+			if run == 0 {
+				selected[hash] = inBurst
+			} else if run == 1 && selected[hash] != inBurst {
+				t.Fatalf("Selection of %s depends on the order of functions.", hash)
+			} else if run == 2 && selected[hash] != inBurst {
+				differentSeed++
+			}
+			if inBurst {
+				count++
+			}
+		}
+
+		if count < 250 || count > 350 {
+			t.Errorf("Expected about 300 of 1000 functions in the burst, got %d.", count)
+		}
+	}
+
+	if differentSeed == 0 {
+		t.Error("Selection of functions does not depend on the seed.")
+	}
+}
+
+func TestBurstParticipantsAcrossParses(t *testing.T) {
+	duration := 10
+
+	// one burst event per minute, which multiplies the invocations of the minute for the functions taking part in it
+	var events []config.BurstEventConfiguration
+	for minute := 0; minute < duration; minute++ {
+		events = append(events, config.BurstEventConfiguration{
+			StartSecond:      float64(minute * 60),
+			DurationSeconds:  60,
+			Amplitude:        3,
+			FunctionFraction: 0.5,
+			ScaleCounts:      true,
+		})
+	}
+
+	invocations := make([]int, duration)
+	for i := range invocations {
+		invocations[i] = 10
+	}
+
+	// each parse of the trace names the function of the same trace row differently
+	participants := func(seed int64, name string) []bool {
+		function := testFunction
+		function.Name = name
+		function.InvocationStats = &common.FunctionInvocationStats{HashOwner: "owner", HashApp: "app", HashFunction: "function", Invocations: invocations}
+
+		sg := NewSpecificationGenerator(seed)
+		sg.SetBurstEvents(NewBurstEvents(events))
+		spec := sg.GenerateInvocationData(&function, common.Exponential, false, common.MinuteGranularity)
+
+		var result []bool
+		for minute, count := range spec.PerMinuteCount {
+			result = append(result, count != invocations[minute])
+		}
+
+		return result
+	}
+
+	inBurst := 0
+	for seed := int64(0); seed < 4; seed++ {
+		first := participants(seed, fmt.Sprintf("%s-0-%d", common.FunctionNamePrefix, 1234))
+		second := participants(seed, fmt.Sprintf("%s-0-%d", common.FunctionNamePrefix, 5678))
+		if !reflect.DeepEqual(first, second) {
+			t.Fatalf("Burst participants differ between two parses of the same trace with seed %d.", seed)
+		}
+
+		for _, participant := range first {
+			if participant {
+				inBurst++
+			}
+		}
+	}
+
+	if inBurst == 0 || inBurst == 4*duration {
+		t.Errorf("Expected the function to take part in some of the bursts, got %d of %d.", inBurst, 4*duration)
+	}
+}
@@ -7,7 +7,6 @@ import (
 	"math/rand"
 	"os"
 	"strconv"
-	"strings"
 	"sync/atomic"
 
 	log "github.com/sirupsen/logrus"
@@ -23,6 +22,8 @@ func printDAG(DAGWorkflow *list.List) {
 	var printMessage string
 	var buffer string = ""
 	var dummyNode *list.Element
+	// joins are shared by the branches of their predecessors, but printed only once
+	printedJoins := make(map[*list.List]bool)
 	var startingNode bool = true
 	for len(nodeQueue) > 0 {
 		DAGNode = nodeQueue[0]
@@ -39,6 +40,13 @@ func printDAG(DAGWorkflow *list.List) {
 			printMessage = printMessage + " -> " + strconv.Itoa(functionId)
 		}
 		for i := 0; i < len(DAGNode.Value.(*common.Node).Branches); i++ {
+			branch := DAGNode.Value.(*common.Node).Branches[i]
+			if branch.Front().Value.(*common.Node).Predecessors > 1 {
+				if printedJoins[branch] {
+					continue
+				}
+				printedJoins[branch] = true
+			}
 			nodeQueue = append(nodeQueue, dummyNode)
 			copy(nodeQueue[1:], nodeQueue)
 			nodeQueue[0] = DAGNode.Value.(*common.Node).Branches[i].Front()
@@ -78,7 +86,7 @@ func generateCDF(file string) [][]float64 {
 	}
 	for i := 0; i < len(records[0]); i += 2 {
 		for j := 0; j < len(records); j++ {
-			cdfProb, _ := strconv.ParseFloat(strings.TrimSuffix(records[j][i+1], "%"), 64)
+			cdfProb, _ := parseCDFProbability(records[j][i+1])
 			cdfValue, _ := strconv.ParseFloat(records[j][i], 64)
 			cdf[i+1][j] = cdfProb
 			cdf[i][j] = cdfValue
@@ -125,6 +133,7 @@ func GenerateDAGs(config *config.LoaderConfiguration, functions []*common.Functi
 	var dagIdentity int = 0
 	var functionLinkedList *list.List
 	totalDAGList := []*list.List{}
+	payload := NewPayloadSize(config.DAGPayloadSize)
 	for {
 		if config.EnableDAGDataset {
 			DAGDistribution := generateCDF(fmt.Sprintf("%s/dag_structure.csv", config.TracePath))
@@ -137,11 +146,22 @@ func GenerateDAGs(config *config.LoaderConfiguration, functions []*common.Functi
 				log.Fatalf("Invalid Width and Depth given")
 			}
 		}
-		if (len(functions) - functionIndex) < (depth-1)*width+1 {
+		requiredFunctions := (depth-1)*width + 1
+		if config.DAGFanIn {
+			requiredFunctions++
+		}
+		if (len(functions) - functionIndex) < requiredFunctions {
 			log.Infof("DAGs created: %d, Total Functions used: %d, Functions Unused: %d", dagIdentity, functionIndex, len(functions)-functionIndex)
 			break
 		}
 		functionLinkedList, functionIndex = createDAGWorkflow(functions, functionIndex, width, depth, dagIdentity)
+		if config.DAGFanIn {
+			joinDAGLeaves(functionLinkedList, functions[functionIndex], depth)
+			functionIndex++
+		}
+		if payload != nil {
+			setDAGPayloadSize(functionLinkedList, payload)
+		}
 		dagIdentity++
 		if !test {
 			printDAG(functionLinkedList)
@@ -194,6 +214,37 @@ func createDAGWorkflow(functionList []*common.Function, functionID int, maxWidth
 	return DAGList, functionID
 }
 
+// joinDAGLeaves adds a node with the function after all the leaves of the DAG, which aggregates their results
+func joinDAGLeaves(DAGList *list.List, function *common.Function, depth int) {
+	var leaves []*common.Node
+	var leafBranch *list.List
+	branches := []*list.List{DAGList}
+	for len(branches) > 0 {
+		branch := branches[0]
+		branches = branches[1:]
+		for element := branch.Front(); element != nil; element = element.Next() {
+			branches = append(branches, element.Value.(*common.Node).Branches...)
+		}
+
+		if leaf := branch.Back().Value.(*common.Node); len(leaf.Branches) == 0 {
+			leaves = append(leaves, leaf)
+			leafBranch = branch
+		}
+	}
+
+	join := &common.Node{Function: function, Depth: depth, DAG: leaves[0].DAG, Predecessors: len(leaves)}
+	if len(leaves) == 1 {
+		leafBranch.PushBack(join)
+		return
+	}
+
+	joinBranch := list.New()
+	joinBranch.PushBack(join)
+	for _, leaf := range leaves {
+		leaf.Branches = []*list.List{joinBranch}
+	}
+}
+
 func addBranches(nodeQueue []*list.Element, widthList []int, node *common.Node, functionList []*common.Function, functionID int, dagIdentifier string) ([]*list.List, []*list.Element) {
 	var additionalBranches int
 	if len(nodeQueue) < 1 || (nodeQueue[0].Value.(*common.Node).Depth > node.Depth) {
@@ -252,6 +303,10 @@ func GetDAGShape(functionLinkedList *list.List, width *int64, depth int) (int, i
 		node := nodeElement.Value.(*common.Node)
 		if len(node.Branches) != 0 {
 			for j := 0; j < len(node.Branches); j++ {
+				// joins narrow the DAG instead of adding to its width
+				if node.Branches[j].Front().Value.(*common.Node).Predecessors > 1 {
+					continue
+				}
 				atomic.AddInt64(width, 1)
 				GetDAGShape(node.Branches[j], width, depth)
 			}
@@ -25,6 +25,7 @@
 package generator
 
 import (
+	"container/list"
 	"fmt"
 	"testing"
 
@@ -135,3 +136,50 @@ func TestGenerateDAGByDataset(t *testing.T) {
 		t.Error("Unable to generate DAGs by Dataset")
 	}
 }
+
+func TestGenerateDAGsWithFanIn(t *testing.T) {
+	var functionList []*common.Function = make([]*common.Function, 11)
+	for i := 0; i < len(functionList); i++ {
+		functionList[i] = functions[0]
+	}
+	fanInConfig := *fakeConfig
+	fanInConfig.EnableDAGDataset = false
+	fanInConfig.Width = 3
+	fanInConfig.Depth = 3
+	fanInConfig.DAGFanIn = true
+
+	dagList := GenerateDAGs(&fanInConfig, functionList, true)
+	if len(dagList) != 1 {
+		t.Fatalf("Expected a single DAG of 8 functions, got %d DAGs.", len(dagList))
+	}
+
+	// every leaf of the DAG is followed by the same join
+	var joins []*list.List
+	branches := []*list.List{dagList[0]}
+	for len(branches) > 0 {
+		leaf := branches[0].Back().Value.(*common.Node)
+		for element := branches[0].Front(); element != nil; element = element.Next() {
+			for _, branch := range element.Value.(*common.Node).Branches {
+				if branch.Front().Value.(*common.Node).Predecessors <= 1 {
+					branches = append(branches, branch)
+				}
+			}
+		}
+		branches = branches[1:]
+
+		if len(leaf.Branches) != 1 {
+			t.Fatalf("Leaf at depth %d should be followed by the join.", leaf.Depth)
+		}
+		joins = append(joins, leaf.Branches[0])
+	}
+
+	join := joins[0].Front().Value.(*common.Node)
+	if len(joins) != fanInConfig.Width || join.Predecessors != fanInConfig.Width || join.Depth != fanInConfig.Depth {
+		t.Errorf("Unexpected join %+v after %d leaves.", join, len(joins))
+	}
+	for _, branch := range joins {
+		if branch != joins[0] {
+			t.Error("Leaves should share the branch of the join.")
+		}
+	}
+}
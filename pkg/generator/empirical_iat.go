@@ -0,0 +1,181 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"encoding/csv"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+// globalCDFKey is the HashFunction of the CDF used for functions without a CDF of their own
+const globalCDFKey = "*"
+
+// EmpiricalCDF is a measured cumulative distribution of IATs, where Probabilities are non-decreasing and end at one
+type EmpiricalCDF struct {
+	Values        []float64
+	Probabilities []float64
+}
+
+// Sample returns the value of the given cumulative probability by linear interpolation between the points of the CDF
+func (cdf *EmpiricalCDF) Sample(probability float64) float64 {
+	i := sort.SearchFloat64s(cdf.Probabilities, probability)
+	if i == 0 {
+		return cdf.Values[0]
+	}
+	if i == len(cdf.Probabilities) {
+		return cdf.Values[len(cdf.Values)-1]
+	}
+
+	lowerProbability, upperProbability := cdf.Probabilities[i-1], cdf.Probabilities[i]
+	if upperProbability == lowerProbability {
+		return cdf.Values[i]
+	}
+
+	fraction := (probability - lowerProbability) / (upperProbability - lowerProbability)
+	return cdf.Values[i-1] + fraction*(cdf.Values[i]-cdf.Values[i-1])
+}
+
+// parseCDFProbability parses a cumulative probability that might be given in percent with the % sign
+func parseCDFProbability(value string) (float64, error) {
+	return strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(value), "%"), 64)
+}
+
+// ReadEmpiricalCDFs reads IAT CDFs from a CSV file with the header value,probability for a single CDF used for all
+// functions, or HashFunction,value,probability for a CDF per function, where the HashFunction * denotes the CDF of the
+// functions not listed. Points of each CDF have to be sorted by value. Probabilities are normalized by the last point,
+// so they can be given both as fractions and in percent. As IATs are normalized to the per-minute invocation counts,
+// the unit of values is irrelevant.
+func ReadEmpiricalCDFs(path string) map[string]*EmpiricalCDF {
+	f, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("Failed to open IAT CDF file %s - %v", path, err)
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		log.Fatalf("Failed to read IAT CDF file %s - %v", path, err)
+	}
+	if len(records) < 2 {
+		log.Fatalf("IAT CDF file %s has no points.", path)
+	}
+
+	perFunction := strings.EqualFold(strings.TrimSpace(records[0][0]), "HashFunction")
+
+	result := make(map[string]*EmpiricalCDF)
+	for i, record := range records[1:] {
+		key := globalCDFKey
+		if perFunction {
+			if len(record) != 3 {
+				log.Fatalf("Line %d of IAT CDF file %s should have 3 columns.", i+2, path)
+			}
+
+			key, record = record[0], record[1:]
+		} else if len(record) != 2 {
+			log.Fatalf("Line %d of IAT CDF file %s should have 2 columns.", i+2, path)
+		}
+
+		value, err := strconv.ParseFloat(strings.TrimSpace(record[0]), 64)
+		common.Check(err)
+		probability, err := parseCDFProbability(record[1])
+		common.Check(err)
+
+		cdf, ok := result[key]
+		if !ok {
+			cdf = &EmpiricalCDF{}
+			result[key] = cdf
+		}
+
+		if n := len(cdf.Values); value < 0 || (n > 0 && (value < cdf.Values[n-1] || probability < cdf.Probabilities[n-1])) {
+			log.Fatalf("Points of the IAT CDF of %s have to be non-negative and sorted (line %d of %s).", key, i+2, path)
+		}
+
+		cdf.Values = append(cdf.Values, value)
+		cdf.Probabilities = append(cdf.Probabilities, probability)
+	}
+
+	for key, cdf := range result {
+		last := cdf.Probabilities[len(cdf.Probabilities)-1]
+		if last <= 0 || cdf.Values[len(cdf.Values)-1] <= 0 {
+			log.Fatalf("IAT CDF of %s in %s has to end with a positive value and probability.", key, path)
+		}
+		if cdf.Values[0] == 0 && cdf.Probabilities[0] > 0 {
+			// IATs of zero are not supported by the invocation loop
+			log.Fatalf("IAT CDF of %s in %s cannot have probability mass at zero.", key, path)
+		}
+
+		for i := range cdf.Probabilities {
+			cdf.Probabilities[i] /= last
+		}
+	}
+
+	log.Infof("Read %d IAT CDFs from %s", len(result), path)
+
+	return result
+}
+
+// SetEmpiricalCDFs sets the CDFs of the empirical IAT distribution by HashFunction
+func (s *SpecificationGenerator) SetEmpiricalCDFs(cdfs map[string]*EmpiricalCDF) {
+	s.empiricalCDFs = cdfs
+}
+
+// empiricalCDF returns the CDF of the function, falling back to the global one
+func (s *SpecificationGenerator) empiricalCDF(function *common.Function) *EmpiricalCDF {
+	if function.InvocationStats != nil {
+		if cdf, ok := s.empiricalCDFs[function.InvocationStats.HashFunction]; ok {
+			return cdf
+		}
+	}
+
+	return s.empiricalCDFs[globalCDFKey]
+}
+
+// selectEmpiricalCDF makes the CDF of the function the one IATs are sampled from by the empirical distribution
+func (s *SpecificationGenerator) selectEmpiricalCDF(function *common.Function, iatDistribution common.IatDistribution) {
+	if iatDistribution != common.Empirical {
+		return
+	}
+
+	s.iatCDF = s.empiricalCDF(function)
+	if s.iatCDF == nil {
+		log.Fatalf("No IAT CDF for function %s and no global one.", function.Name)
+	}
+}
+
+// sampleEmpirical draws from the CDF by inverse transform sampling, excluding the probability zero, which could yield
+// an IAT of zero
+func sampleEmpirical(gen *rand.Rand, cdf *EmpiricalCDF) float64 {
+	return cdf.Sample(1 - gen.Float64())
+}
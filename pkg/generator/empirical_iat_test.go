@@ -0,0 +1,112 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"gonum.org/v1/gonum/stat"
+)
+
+func writeCDFFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "iat_cdf.csv")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestReadEmpiricalCDFs(t *testing.T) {
+	cdfs := ReadEmpiricalCDFs(writeCDFFile(t, "value,probability\n0,0%\n10,50%\n30,100%\n"))
+	if len(cdfs) != 1 || cdfs[globalCDFKey] == nil {
+		t.Fatalf("Expected a single global CDF, got %v.", cdfs)
+	}
+
+	cdf := cdfs[globalCDFKey]
+	for _, test := range []struct {
+		probability float64
+		value       float64
+	}{
+		{0, 0},
+		{0.25, 5},
+		{0.5, 10},
+		{0.75, 20},
+		{1, 30},
+	} {
+		if value := cdf.Sample(test.probability); math.Abs(value-test.value) > 1e-9 {
+			t.Errorf("Expected value %f at probability %f, got %f.", test.value, test.probability, value)
+		}
+	}
+
+	cdfs = ReadEmpiricalCDFs(writeCDFFile(t, "HashFunction,value,probability\n"+
+		"f1,1,0.5\nf1,2,1\n"+
+		"*,100,0.2\n*,200,1\n"))
+	if len(cdfs) != 2 || cdfs["f1"].Values[1] != 2 || cdfs[globalCDFKey].Probabilities[0] != 0.2 {
+		t.Errorf("Unexpected per-function CDFs %v.", cdfs)
+	}
+}
+
+func TestEmpiricalIATDistribution(t *testing.T) {
+	sg := NewSpecificationGenerator(123)
+	sg.SetEmpiricalCDFs(map[string]*EmpiricalCDF{
+		// IATs of 1 and 9 with equal probability
+		globalCDFKey: {Values: []float64{1, 1, 9, 9}, Probabilities: []float64{0, 0.5, 0.5, 1}},
+		// constant IATs
+		"constant": {Values: []float64{5, 5}, Probabilities: []float64{0, 1}},
+	})
+
+	function := &common.Function{Name: "bimodal", InvocationStats: &common.FunctionInvocationStats{HashFunction: "other"}}
+	sg.selectEmpiricalCDF(function, common.Empirical)
+
+	iat, _ := sg.generateIATPerGranularity(10_000, common.Empirical, false, common.MinuteGranularity)
+	if len(iat) != 10_001 || iat[0] != 0 {
+		t.Fatalf("Unexpected IATs %v.", iat[:10])
+	}
+
+	// IATs are normalized to the minute, so the short ones are 1/5 of the mean and the long ones 9/5
+	if sum := stat.Mean(iat, nil) * float64(len(iat)); math.Abs(sum-60*common.OneSecondInMicroseconds) > 1 {
+		t.Errorf("IATs sum up to %f μs instead of a minute.", sum)
+	}
+	mean := stat.Mean(iat[1:], nil)
+	for _, value := range iat[1:] {
+		if math.Abs(value-mean/5) > 0.05*mean && math.Abs(value-9*mean/5) > 0.05*mean {
+			t.Fatalf("IAT %f does not follow the CDF with mean %f.", value, mean)
+		}
+	}
+
+	function.InvocationStats.HashFunction = "constant"
+	sg.selectEmpiricalCDF(function, common.Empirical)
+	iat, _ = sg.generateIATPerGranularity(100, common.Empirical, false, common.MinuteGranularity)
+	for _, value := range iat[1:] {
+		if math.Abs(value-600_000) > 1e-6 {
+			t.Fatalf("Expected constant IATs of 600 ms, got %f.", value)
+		}
+	}
+}
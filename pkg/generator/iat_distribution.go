@@ -0,0 +1,101 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"math"
+	"math/rand"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+// defaultIATShapes are used when no shape is configured, chosen to be burstier than Poisson arrivals, i.e., with a
+// coefficient of variation above one
+var defaultIATShapes = map[common.IatDistribution]float64{
+	common.Gamma:     0.5,
+	common.Weibull:   0.7,
+	common.Lognormal: 1.5,
+	common.Pareto:    2.2,
+}
+
+// SetIATShape sets the shape parameter of the gamma (k), Weibull (k), lognormal (σ) and Pareto (α) IAT
+// distributions. The scale of these distributions is irrelevant, as IATs are normalized to the number of invocations
+// in each time unit. Zero selects the default shape of the distribution.
+func (s *SpecificationGenerator) SetIATShape(shape float64) {
+	if shape < 0 {
+		log.Fatalf("Invalid IAT distribution shape %f.", shape)
+	}
+
+	s.iatShape = shape
+}
+
+func (s *SpecificationGenerator) shapeOf(iatDistribution common.IatDistribution) float64 {
+	if s.iatShape > 0 {
+		return s.iatShape
+	}
+
+	return defaultIATShapes[iatDistribution]
+}
+
+// sampleGamma draws from the gamma distribution with the given shape and unit scale using the method of Marsaglia and
+// Tsang, boosting shapes below one as Gamma(k) = Gamma(k+1) * U^(1/k)
+func sampleGamma(gen *rand.Rand, shape float64) float64 {
+	if shape < 1 {
+		return sampleGamma(gen, shape+1) * math.Pow(1-gen.Float64(), 1/shape)
+	}
+
+	d := shape - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+
+	for {
+		x := gen.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+
+		v = v * v * v
+		u := 1 - gen.Float64()
+		if math.Log(u) < 0.5*x*x+d-d*v+d*math.Log(v) {
+			return d * v
+		}
+	}
+}
+
+// sampleWeibull draws from the Weibull distribution with the given shape and unit scale by inverse transform sampling
+func sampleWeibull(gen *rand.Rand, shape float64) float64 {
+	return math.Pow(gen.ExpFloat64(), 1/shape)
+}
+
+// sampleLognormal draws from the lognormal distribution with the given σ and μ = 0
+func sampleLognormal(gen *rand.Rand, sigma float64) float64 {
+	return math.Exp(sigma * gen.NormFloat64())
+}
+
+// samplePareto draws from the Pareto distribution with the given shape and unit minimum by inverse transform sampling
+func samplePareto(gen *rand.Rand, shape float64) float64 {
+	return math.Pow(1-gen.Float64(), -1/shape)
+}
@@ -0,0 +1,100 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"gonum.org/v1/gonum/stat"
+)
+
+func TestIATDistributionSamplers(t *testing.T) {
+	tests := []struct {
+		testName     string
+		sample       func(gen *rand.Rand, shape float64) float64
+		shape        float64
+		expectedMean float64
+		expectedStd  float64
+	}{
+		{"gamma_below_one", sampleGamma, 0.5, 0.5, math.Sqrt(0.5)},
+		{"gamma_above_one", sampleGamma, 3, 3, math.Sqrt(3)},
+		{"weibull", sampleWeibull, 0.7, math.Gamma(1 + 1/0.7), math.Sqrt(math.Gamma(1+2/0.7) - math.Pow(math.Gamma(1+1/0.7), 2))},
+		{"lognormal", sampleLognormal, 1, math.Exp(0.5), math.Sqrt((math.E - 1) * math.E)},
+		{"pareto", samplePareto, 4.5, 4.5 / 3.5, math.Sqrt(4.5 / (3.5 * 3.5 * 2.5))},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			gen := rand.New(rand.NewSource(42))
+
+			samples := make([]float64, 500_000)
+			for i := range samples {
+				samples[i] = test.sample(gen, test.shape)
+				if samples[i] <= 0 {
+					t.Fatalf("Sampled non-positive IAT %f.", samples[i])
+				}
+			}
+
+			mean, std := stat.MeanStdDev(samples, nil)
+			if math.Abs(mean-test.expectedMean) > 0.02*test.expectedMean {
+				t.Errorf("Expected mean %f, got %f.", test.expectedMean, mean)
+			}
+			if math.Abs(std-test.expectedStd) > 0.05*test.expectedStd {
+				t.Errorf("Expected standard deviation %f, got %f.", test.expectedStd, std)
+			}
+		})
+	}
+}
+
+func TestBurstyIATDistributions(t *testing.T) {
+	for _, distribution := range []common.IatDistribution{common.Gamma, common.Weibull, common.Lognormal, common.Pareto} {
+		sg := NewSpecificationGenerator(123)
+
+		iat, _ := sg.generateIATPerGranularity(10_000, distribution, false, common.MinuteGranularity)
+		if len(iat) != 10_001 || iat[0] != 0 {
+			t.Fatalf("Unexpected IATs of distribution %d.", distribution)
+		}
+
+		// IATs are normalized to the minute
+		if sum := stat.Mean(iat, nil) * float64(len(iat)); math.Abs(sum-60*common.OneSecondInMicroseconds) > 1 {
+			t.Errorf("IATs of distribution %d sum up to %f μs instead of a minute.", distribution, sum)
+		}
+
+		// default shapes are burstier than Poisson arrivals
+		if mean, std := stat.MeanStdDev(iat[1:], nil); std/mean <= 1 {
+			t.Errorf("Coefficient of variation of distribution %d is %f, expected above 1.", distribution, std/mean)
+		}
+	}
+
+	sg := NewSpecificationGenerator(123)
+	sg.SetIATShape(100)
+	iat, _ := sg.generateIATPerGranularity(10_000, common.Gamma, false, common.MinuteGranularity)
+	if mean, std := stat.MeanStdDev(iat[1:], nil); std/mean > 0.2 {
+		t.Errorf("Configured shape not applied, coefficient of variation is %f.", std/mean)
+	}
+}
@@ -0,0 +1,131 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+// invocationWindowLength is the number of time units of the trace read at once by streams of streamed traces
+const invocationWindowLength = 60
+
+// InvocationStream generates IATs and runtime specifications of a function lazily, one time unit of the trace at a
+// time, so that only the current window has to be kept in memory. The invocations of streamed traces are read in
+// windows of invocationWindowLength time units as the stream advances. Concatenating all the windows yields the same
+// specification as GenerateInvocationData does with the generator of the stream.
+type InvocationStream struct {
+	generator *SpecificationGenerator
+	function  *common.Function
+
+	arrivalModel    ArrivalModel
+	iatDistribution common.IatDistribution
+	shiftIAT        bool
+	granularity     common.TraceGranularity
+
+	timeUnit int
+	// time from the last generated invocation to the end of the last generated time unit
+	carry float64
+
+	// invocations of the trace from the time unit windowStart on
+	window      []int
+	windowStart int
+}
+
+// NewInvocationStream creates a stream with its own generator seeded from the seed of this one and the function, so the
+// streams are reproducible for the same seed regardless of the order they are created and consumed in
+func (s *SpecificationGenerator) NewInvocationStream(function *common.Function, iatDistribution common.IatDistribution, shiftIAT bool, granularity common.TraceGranularity) *InvocationStream {
+	identity := functionIdentity(function)
+
+	generator := NewSpecificationGenerator(s.seed)
+	generator.seedFunction(identity)
+	generator.SetArrivalModels(s.arrivalModels)
+	generator.SetIATShape(s.iatShape)
+	generator.SetEmpiricalCDFs(s.empiricalCDFs)
+	generator.SetRuntimeMemoryCorrelation(s.runtimeMemoryCorrelation)
+	generator.SetBurstEvents(s.burstEvents)
+	generator.selectBurstEvents(identity)
+	generator.selectEmpiricalCDF(function, iatDistribution)
+
+	return &InvocationStream{
+		generator: generator,
+		function:  function,
+
+		arrivalModel:    s.arrivalModel(function, granularity),
+		iatDistribution: iatDistribution,
+		shiftIAT:        shiftIAT,
+		granularity:     granularity,
+	}
+}
+
+// Next returns the IATs and runtime specifications of the next time unit that has invocations. The first IAT is
+// relative to the last invocation of the previous window, or to the beginning of the experiment for the first window.
+// ok is false once the whole trace has been generated.
+func (is *InvocationStream) Next() (iat common.IATArray, runtime common.RuntimeSpecificationArray, ok bool) {
+	for is.timeUnit < is.function.InvocationStats.TimeUnits() {
+		timeUnitIAT, _ := is.generator.generateBurstyIATPerTimeUnit(is.timeUnit, is.invocations(is.timeUnit), is.arrivalModel, is.iatDistribution, is.shiftIAT, is.granularity)
+		is.timeUnit++
+
+		count := len(timeUnitIAT) - 1
+		if count == 0 {
+			is.carry += timeUnitIAT[0]
+			continue
+		}
+
+		iat = make(common.IATArray, count)
+		iat[0] = is.carry + timeUnitIAT[0]
+		copy(iat[1:], timeUnitIAT[1:count])
+		is.carry = timeUnitIAT[count]
+
+		runtime = make(common.RuntimeSpecificationArray, count)
+		for i := 0; i < count; i++ {
+			runtime[i] = is.generator.generateExecutionSpecs(is.function)
+		}
+
+		return iat, runtime, true
+	}
+
+	return nil, nil, false
+}
+
+// TimeUnit returns the time unit of the trace of the window last returned by Next
+func (is *InvocationStream) TimeUnit() int {
+	return is.timeUnit - 1
+}
+
+// invocations returns the number of invocations of the time unit in the trace, reading the window of the time unit if
+// it has not been read yet
+func (is *InvocationStream) invocations(timeUnit int) int {
+	stats := is.function.InvocationStats
+	if stats.Windows == nil {
+		return stats.Invocations[timeUnit]
+	}
+
+	if timeUnit < is.windowStart || timeUnit >= is.windowStart+len(is.window) {
+		is.windowStart = timeUnit
+		is.window = stats.InvocationWindow(timeUnit, common.MinOf(timeUnit+invocationWindowLength, stats.TimeUnits()))
+	}
+
+	return is.window[timeUnit-is.windowStart]
+}
@@ -0,0 +1,191 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+func TestInvocationStream(t *testing.T) {
+	tests := []struct {
+		testName        string
+		invocations     []int
+		iatDistribution common.IatDistribution
+		granularity     common.TraceGranularity
+	}{
+		{
+			testName:        "stream_exponential",
+			invocations:     []int{5, 0, 0, 3, 10, 0},
+			iatDistribution: common.Exponential,
+			granularity:     common.MinuteGranularity,
+		},
+		{
+			testName:        "stream_equidistant",
+			invocations:     []int{0, 2, 1, 0, 4},
+			iatDistribution: common.Equidistant,
+			granularity:     common.MinuteGranularity,
+		},
+		{
+			testName:        "stream_second_granularity",
+			invocations:     []int{1, 0, 2, 0, 0, 1},
+			iatDistribution: common.Uniform,
+			granularity:     common.SecondGranularity,
+		},
+		{
+			testName:        "stream_no_invocations",
+			invocations:     []int{0, 0},
+			iatDistribution: common.Exponential,
+			granularity:     common.MinuteGranularity,
+		},
+	}
+
+	var seed int64 = 123456789
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			function := testFunction
+			function.InvocationStats = &common.FunctionInvocationStats{Invocations: test.invocations}
+
+			stream := NewSpecificationGenerator(seed).NewInvocationStream(&function, test.iatDistribution, false, test.granularity)
+
+			var iat common.IATArray
+			var runtime common.RuntimeSpecificationArray
+			for {
+				windowIAT, windowRuntime, ok := stream.Next()
+				if !ok {
+					break
+				}
+
+				if len(windowIAT) == 0 || len(windowIAT) != len(windowRuntime) {
+					t.Fatalf("Invalid window with %d IATs and %d runtime specifications.", len(windowIAT), len(windowRuntime))
+				}
+
+				iat = append(iat, windowIAT...)
+				runtime = append(runtime, windowRuntime...)
+			}
+
+			// the stream has to produce the same specification as eager generation, as both are seeded per function
+			expected := NewSpecificationGenerator(seed).GenerateInvocationData(&function, test.iatDistribution, false, test.granularity)
+
+			if len(iat) == 0 && len(expected.IAT) == 0 {
+				return
+			}
+
+			if !reflect.DeepEqual(iat, expected.IAT) {
+				t.Errorf("IATs do not match eager generation - got %v, expected %v.", iat, expected.IAT)
+			}
+			if !reflect.DeepEqual(runtime, expected.RuntimeSpecification) {
+				t.Error("Runtime specifications do not match eager generation.")
+			}
+		})
+	}
+}
+
+func TestInvocationStreamDeterminism(t *testing.T) {
+	functions := make([]common.Function, 3)
+	for i := range functions {
+		functions[i] = testFunction
+		functions[i].InvocationStats = &common.FunctionInvocationStats{Invocations: []int{3, 1, 4}}
+	}
+
+	consumeStreams := func(order []int) []common.IATArray {
+		generator := NewSpecificationGenerator(42)
+
+		var streams []*InvocationStream
+		for i := range functions {
+			streams = append(streams, generator.NewInvocationStream(&functions[i], common.Exponential, false, common.MinuteGranularity))
+		}
+
+		result := make([]common.IATArray, len(streams))
+		for _, i := range order {
+			for {
+				iat, _, ok := streams[i].Next()
+				if !ok {
+					break
+				}
+
+				result[i] = append(result[i], iat...)
+			}
+		}
+
+		return result
+	}
+
+	// the order in which the streams are consumed must not change their content
+	if !reflect.DeepEqual(consumeStreams([]int{0, 1, 2}), consumeStreams([]int{2, 0, 1})) {
+		t.Error("Invocation streams are not deterministic.")
+	}
+}
+
+// sliceWindowReader serves the invocations of a slice in windows and records the windows that were read
+type sliceWindowReader struct {
+	invocations []int
+	reads       int
+}
+
+func (r *sliceWindowReader) TimeUnits() int {
+	return len(r.invocations)
+}
+
+func (r *sliceWindowReader) ReadWindow(start int, end int) []int {
+	r.reads++
+	return append([]int{}, r.invocations[start:end]...)
+}
+
+func TestInvocationStreamWindows(t *testing.T) {
+	invocations := make([]int, 3*invocationWindowLength+10)
+	for i := range invocations {
+		invocations[i] = i % 4
+	}
+
+	var seed int64 = 42
+
+	function := testFunction
+	reader := &sliceWindowReader{invocations: invocations}
+	function.InvocationStats = &common.FunctionInvocationStats{Windows: reader}
+
+	stream := NewSpecificationGenerator(seed).NewInvocationStream(&function, common.Exponential, false, common.MinuteGranularity)
+
+	var iat common.IATArray
+	for windowIAT, _, ok := stream.Next(); ok; windowIAT, _, ok = stream.Next() {
+		iat = append(iat, windowIAT...)
+	}
+
+	// the trace is read window by window as the stream advances
+	if reader.reads != 4 {
+		t.Errorf("Expected the trace to be read in 4 windows, got %d.", reader.reads)
+	}
+
+	eager := testFunction
+	eager.InvocationStats = &common.FunctionInvocationStats{Invocations: invocations}
+	expected := NewSpecificationGenerator(seed).GenerateInvocationData(&eager, common.Exponential, false, common.MinuteGranularity)
+
+	if !reflect.DeepEqual(iat, expected.IAT) {
+		t.Error("IATs of the stream read in windows do not match eager generation.")
+	}
+}
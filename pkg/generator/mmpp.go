@@ -0,0 +1,258 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"math"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+// MMPP is a Markov-modulated Poisson process, i.e., a Poisson process whose rate is determined by the state of a
+// continuous-time Markov chain. As the number of invocations in each time unit is given by the trace, only the
+// relative rates of the states matter.
+type MMPP struct {
+	// arrival rate of each state relative to the other states
+	Rates []float64
+	// Transitions[i][j] is the rate of switching from state i to state j in 1/s, where the diagonal is ignored
+	Transitions [][]float64
+}
+
+// rateSegment is a period of time in μs during which invocations arrive at a constant relative rate
+type rateSegment struct {
+	start, end float64
+	rate       float64
+}
+
+// cumulativeRates returns the number of arrivals expected by the end of each segment, relative to its rate
+func cumulativeRates(segments []rateSegment) []float64 {
+	result := make([]float64, len(segments))
+
+	total := 0.0
+	for i, segment := range segments {
+		total += segment.rate * (segment.end - segment.start)
+		result[i] = total
+	}
+
+	return result
+}
+
+// timeOfCumulativeRate returns the time at which the cumulative rate of the segments reaches the given value, which
+// has to be below the total
+func timeOfCumulativeRate(segments []rateSegment, cumulative []float64, value float64) float64 {
+	i := sort.SearchFloat64s(cumulative, value)
+	for segments[i].rate == 0 {
+		// segments without arrivals do not add to the cumulative rate, so the value lies in the next segment
+		i++
+	}
+
+	return segments[i].end - (cumulative[i]-value)/segments[i].rate
+}
+
+// validate terminates the loader if the MMPP cannot generate arrivals
+func (m *MMPP) validate(name string) {
+	if len(m.Rates) == 0 || len(m.Transitions) != len(m.Rates) {
+		log.Fatalf("MMPP of %s needs a transition matrix with a row per state.", name)
+	}
+
+	positiveRate := false
+	for i, rate := range m.Rates {
+		if rate < 0 || len(m.Transitions[i]) != len(m.Rates) {
+			log.Fatalf("MMPP of %s needs non-negative rates and a square transition matrix.", name)
+		}
+		for _, transition := range m.Transitions[i] {
+			if transition < 0 {
+				log.Fatalf("MMPP of %s cannot have negative transition rates.", name)
+			}
+		}
+
+		positiveRate = positiveRate || rate > 0
+	}
+
+	if !positiveRate {
+		log.Fatalf("MMPP of %s needs a state with a positive rate.", name)
+	}
+}
+
+// FitMMPP fits a two-state MMPP to the variation of invocations between time units of the given length in μs. Time
+// units with more invocations than the average belong to the high state, the others to the low state, whose rates are
+// the average number of invocations of their time units. The transition rates are the inverse of the average number of
+// consecutive time units spent in a state. Functions without variation get a single state, i.e., Poisson arrivals.
+func FitMMPP(invocations []int, timeUnit float64) *MMPP {
+	poisson := &MMPP{Rates: []float64{1}, Transitions: [][]float64{{0}}}
+
+	total := 0
+	for _, count := range invocations {
+		total += count
+	}
+	if total == 0 {
+		return poisson
+	}
+	mean := float64(total) / float64(len(invocations))
+
+	// index 0 is the low state and 1 the high one
+	var sum, units, runs [2]float64
+	for i, count := range invocations {
+		state := 0
+		if float64(count) > mean {
+			state = 1
+		}
+
+		sum[state] += float64(count)
+		units[state]++
+		if i == 0 || (float64(invocations[i-1]) > mean) != (state == 1) {
+			runs[state]++
+		}
+	}
+
+	if units[0] == 0 || units[1] == 0 {
+		return poisson
+	}
+
+	timeUnitSeconds := timeUnit / common.OneSecondInMicroseconds
+	return &MMPP{
+		Rates: []float64{sum[0] / units[0], sum[1] / units[1]},
+		Transitions: [][]float64{
+			{0, runs[0] / (units[0] * timeUnitSeconds)},
+			{runs[1] / (units[1] * timeUnitSeconds), 0},
+		},
+	}
+}
+
+// simulateMMPPChain simulates the modulating chain for the given duration in μs starting from the current state of the
+// generator, which is then left in the state reached at the end, so the chain continues in the next time unit
+func (s *SpecificationGenerator) simulateMMPPChain(mmpp *MMPP, duration float64) []rateSegment {
+	var segments []rateSegment
+
+	for t := 0.0; t < duration; {
+		exitRate := 0.0
+		for next, transition := range mmpp.Transitions[s.mmppState] {
+			if next != s.mmppState {
+				exitRate += transition
+			}
+		}
+
+		end := duration
+		if exitRate > 0 {
+			end = math.Min(duration, t+s.iatRand.ExpFloat64()/exitRate*common.OneSecondInMicroseconds)
+		}
+		segments = append(segments, rateSegment{start: t, end: end, rate: mmpp.Rates[s.mmppState]})
+
+		if end < duration {
+			// the next state is chosen proportionally to the transition rates
+			choice := s.iatRand.Float64() * exitRate
+			for next, transition := range mmpp.Transitions[s.mmppState] {
+				if next == s.mmppState || transition == 0 {
+					continue
+				}
+
+				choice -= transition
+				if choice < 0 {
+					s.mmppState = next
+					break
+				}
+			}
+		}
+
+		t = end
+	}
+
+	return segments
+}
+
+// generateMMPPIAT generates IATs for one time unit of an MMPP. Given the number of invocations and the path of the
+// chain, arrivals of a Poisson process are independent and distributed proportionally to the rate of the chain over
+// time, so the invocations are placed accordingly, which preserves the per-time-unit count. The IATs have the same
+// layout as the ones of generateIATPerGranularity without shifting, with the offset of the first invocation in front.
+func (s *SpecificationGenerator) generateMMPPIAT(numberOfInvocations int, mmpp *MMPP, granularity common.TraceGranularity) ([]float64, float64) {
+	timeUnit := getBlankTimeUnit(granularity)
+	segments := s.simulateMMPPChain(mmpp, timeUnit)
+
+	if numberOfInvocations == 0 {
+		return []float64{timeUnit}, 0.0
+	}
+
+	cumulativeWeight := cumulativeRates(segments)
+	totalWeight := cumulativeWeight[len(cumulativeWeight)-1]
+
+	if totalWeight == 0 {
+		// the chain stayed in states without arrivals, while the trace still has invocations in the time unit
+		for i := range segments {
+			segments[i].rate = 1
+		}
+		cumulativeWeight = cumulativeRates(segments)
+		totalWeight = timeUnit
+	}
+
+	arrivals := make([]float64, numberOfInvocations)
+	for i := range arrivals {
+		arrivals[i] = timeOfCumulativeRate(segments, cumulativeWeight, s.iatRand.Float64()*totalWeight)
+	}
+	sort.Float64s(arrivals)
+
+	iatResult := []float64{arrivals[0]}
+	for i := 1; i < len(arrivals); i++ {
+		iatResult = append(iatResult, arrivals[i]-arrivals[i-1])
+	}
+	iatResult = append(iatResult, timeUnit-arrivals[len(arrivals)-1])
+
+	return iatResult, totalWeight
+}
+
+// IndexOfDispersion returns the variance-to-mean ratio of the number of invocations in consecutive windows of the
+// given length in μs, which is one for Poisson arrivals and grows with burstiness
+func IndexOfDispersion(iat common.IATArray, window float64) float64 {
+	var counts []float64
+
+	timestamp := 0.0
+	for _, value := range iat {
+		timestamp += value
+
+		index := int(timestamp / window)
+		for len(counts) <= index {
+			counts = append(counts, 0)
+		}
+		counts[index]++
+	}
+
+	if len(counts) == 0 {
+		return 0
+	}
+
+	mean, variance := 0.0, 0.0
+	for _, count := range counts {
+		mean += count
+	}
+	mean /= float64(len(counts))
+
+	for _, count := range counts {
+		variance += (count - mean) * (count - mean)
+	}
+	variance /= float64(len(counts))
+
+	return variance / mean
+}
@@ -0,0 +1,99 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"math"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+func TestMMPPHonorsPerMinuteCounts(t *testing.T) {
+	invocations := []int{100, 0, 1, 500, 30}
+
+	sg := NewSpecificationGenerator(42)
+	sg.SetArrivalModels(NewArrivalModels(map[string]config.ArrivalModelConfiguration{
+		"http": {Model: "mmpp", MMPPRates: []float64{0, 1}, MMPPTransitions: [][]float64{{0, 0.2}, {0.5, 0}}},
+	}))
+
+	spec := sg.GenerateInvocationData(createTriggeredFunction("http", invocations), common.Exponential, false, common.MinuteGranularity)
+
+	timestamp := 0.0
+	counts := make([]int, len(invocations))
+	for _, iat := range spec.IAT {
+		if iat < 0 {
+			t.Fatalf("Negative IAT %f.", iat)
+		}
+
+		timestamp += iat
+		counts[int(timestamp/60_000_000)]++
+	}
+
+	for i := range invocations {
+		if counts[i] != invocations[i] || spec.PerMinuteCount[i] != invocations[i] {
+			t.Errorf("Expected %d invocations in minute %d, got %d.", invocations[i], i, counts[i])
+		}
+	}
+}
+
+func TestMMPPIndexOfDispersion(t *testing.T) {
+	invocations := make([]int, 30)
+	for i := range invocations {
+		invocations[i] = 600
+	}
+
+	poisson := NewSpecificationGenerator(42).GenerateInvocationData(createTriggeredFunction("http", invocations), common.Exponential, false, common.MinuteGranularity)
+	if dispersion := IndexOfDispersion(poisson.IAT, 1_000_000); math.Abs(dispersion-1) > 0.15 {
+		t.Errorf("Expected index of dispersion around 1 for Poisson arrivals, got %f.", dispersion)
+	}
+
+	// on/off arrivals with on and off periods of 5 s on average
+	sg := NewSpecificationGenerator(42)
+	sg.SetArrivalModels(NewArrivalModels(map[string]config.ArrivalModelConfiguration{
+		"http": {Model: "mmpp", MMPPRates: []float64{0, 1}, MMPPTransitions: [][]float64{{0, 0.2}, {0.2, 0}}},
+	}))
+	bursty := sg.GenerateInvocationData(createTriggeredFunction("http", invocations), common.Exponential, false, common.MinuteGranularity)
+	if dispersion := IndexOfDispersion(bursty.IAT, 1_000_000); dispersion < 5 {
+		t.Errorf("Expected index of dispersion well above 1 for on/off arrivals, got %f.", dispersion)
+	}
+}
+
+func TestFitMMPP(t *testing.T) {
+	mmpp := FitMMPP([]int{0, 0, 10, 10, 0, 0, 0, 0, 10, 10}, 60_000_000)
+
+	if mmpp.Rates[0] != 0 || mmpp.Rates[1] != 10 {
+		t.Errorf("Unexpected rates %v.", mmpp.Rates)
+	}
+	// low for 3 minutes and high for 2 minutes on average
+	if math.Abs(mmpp.Transitions[0][1]-1.0/180) > 1e-9 || math.Abs(mmpp.Transitions[1][0]-1.0/120) > 1e-9 {
+		t.Errorf("Unexpected transition rates %v.", mmpp.Transitions)
+	}
+
+	if constant := FitMMPP([]int{5, 5, 5}, 60_000_000); len(constant.Rates) != 1 {
+		t.Errorf("Expected Poisson arrivals without variation, got %v.", constant)
+	}
+}
@@ -0,0 +1,146 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"container/list"
+	"math/rand"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+// NewPayloadSize validates the payload size distribution of edges of a DAG, where nil stands for no payload
+func NewPayloadSize(cfg *config.PayloadSizeConfiguration) *common.PayloadSize {
+	if cfg == nil {
+		return nil
+	}
+
+	payload := &common.PayloadSize{
+		MeanBytes: cfg.MeanBytes,
+		MinBytes:  cfg.MinBytes,
+		MaxBytes:  cfg.MaxBytes,
+		Sigma:     cfg.Sigma,
+	}
+
+	switch strings.ToLower(cfg.Distribution) {
+	case "", "constant":
+		payload.Distribution = common.ConstantPayload
+	case "uniform":
+		payload.Distribution = common.UniformPayload
+		if cfg.MaxBytes < cfg.MinBytes || cfg.MaxBytes <= 0 {
+			log.Fatal("Uniform payload sizes need MaxBytes above MinBytes.")
+		}
+	case "exponential":
+		payload.Distribution = common.ExponentialPayload
+	case "lognormal":
+		payload.Distribution = common.LognormalPayload
+		if cfg.Sigma < 0 {
+			log.Fatal("Lognormal payload sizes need a non-negative Sigma.")
+		}
+	default:
+		log.Fatalf("Unsupported payload size distribution %s.", cfg.Distribution)
+	}
+
+	if payload.Distribution != common.UniformPayload && cfg.MeanBytes <= 0 {
+		log.Fatalf("Payload sizes of the %s distribution need a positive MeanBytes.", cfg.Distribution)
+	}
+	if cfg.MinBytes < 0 || (cfg.MaxBytes > 0 && cfg.MaxBytes < cfg.MinBytes) {
+		log.Fatal("Payload size bounds have to be non-negative and MaxBytes cannot be below MinBytes.")
+	}
+
+	return payload
+}
+
+// dagNodes returns the elements of all nodes of the DAG, visiting the branches shared by several nodes once
+func dagNodes(DAGList *list.List) []*list.Element {
+	var result []*list.Element
+
+	visited := make(map[*list.List]bool)
+	branches := []*list.List{DAGList}
+	for len(branches) > 0 {
+		branch := branches[0]
+		branches = branches[1:]
+		if visited[branch] {
+			continue
+		}
+		visited[branch] = true
+
+		for element := branch.Front(); element != nil; element = element.Next() {
+			result = append(result, element)
+			branches = append(branches, element.Value.(*common.Node).Branches...)
+		}
+	}
+
+	return result
+}
+
+// setDAGPayloadSize sets the payload size of all nodes of the DAG but its root
+func setDAGPayloadSize(DAGList *list.List, payload *common.PayloadSize) {
+	for _, element := range dagNodes(DAGList) {
+		if element != DAGList.Front() {
+			element.Value.(*common.Node).Payload = payload
+		}
+	}
+}
+
+// SamplePayloadSizes samples, for each invocation of the DAG, the size of the data each predecessor passes to each of
+// its nodes. The sizes of a node are drawn from a source seeded from the seed, the DAG and the trace row of the
+// function of the node, so they are reproducible regardless of the order the nodes are invoked in.
+func SamplePayloadSizes(DAGList *list.List, seed int64) {
+	for _, element := range dagNodes(DAGList) {
+		node := element.Value.(*common.Node)
+		if node.Payload == nil || node.Function.Specification == nil {
+			continue
+		}
+
+		r := rand.New(rand.NewSource(functionSeed(seed, node.DAG+node.Stage+"/"+functionIdentity(node.Function))))
+		node.PayloadSizes = make([][]int, len(node.Function.Specification.RuntimeSpecification))
+		for i := range node.PayloadSizes {
+			node.PayloadSizes[i] = make([]int, max(node.Predecessors, 1))
+			for j := range node.PayloadSizes[i] {
+				node.PayloadSizes[i][j] = node.Payload.Sample(r)
+			}
+		}
+	}
+}
+
+// MaxPayloadSize returns the size of the largest data passed to a node of the DAG in any of its invocations
+func MaxPayloadSize(DAGList *list.List) int {
+	result := 0
+	for _, element := range dagNodes(DAGList) {
+		for _, sizes := range element.Value.(*common.Node).PayloadSizes {
+			size := 0
+			for _, predecessorSize := range sizes {
+				size += predecessorSize
+			}
+			result = max(result, size)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,186 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"container/list"
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	"gonum.org/v1/gonum/stat"
+)
+
+func TestPayloadSize(t *testing.T) {
+	for _, test := range []struct {
+		cfg          config.PayloadSizeConfiguration
+		mean         float64
+		minimum, max int
+	}{
+		{cfg: config.PayloadSizeConfiguration{MeanBytes: 1000}, mean: 1000, minimum: 1000, max: 1000},
+		{cfg: config.PayloadSizeConfiguration{Distribution: "uniform", MinBytes: 100, MaxBytes: 300}, mean: 200, minimum: 100, max: 300},
+		{cfg: config.PayloadSizeConfiguration{Distribution: "exponential", MeanBytes: 1000}, mean: 1000, minimum: 0, max: math.MaxInt},
+		{cfg: config.PayloadSizeConfiguration{Distribution: "lognormal", MeanBytes: 1000, Sigma: 0.5}, mean: 1000, minimum: 0, max: math.MaxInt},
+		{cfg: config.PayloadSizeConfiguration{Distribution: "exponential", MeanBytes: 100_000, MinBytes: 10, MaxBytes: 50}, mean: 50, minimum: 10, max: 50},
+	} {
+		payload := NewPayloadSize(&test.cfg)
+		r := rand.New(rand.NewSource(42))
+
+		sizes := make([]float64, 100_000)
+		for i := range sizes {
+			size := payload.Sample(r)
+			if size < test.minimum || size > test.max {
+				t.Fatalf("Payload size %d of %+v is out of bounds.", size, test.cfg)
+			}
+			sizes[i] = float64(size)
+		}
+
+		if mean := stat.Mean(sizes, nil); math.Abs(mean-test.mean) > 0.02*test.mean {
+			t.Errorf("Expected mean payload size %f of %+v, got %f.", test.mean, test.cfg, mean)
+		}
+	}
+
+	if NewPayloadSize(nil) != nil {
+		t.Error("Edges without payload configuration should not pass data.")
+	}
+}
+
+func TestDAGPayloadSize(t *testing.T) {
+	var functionList []*common.Function = make([]*common.Function, 7)
+	for i := 0; i < len(functionList); i++ {
+		functionList[i] = functions[0]
+	}
+	payloadConfig := *fakeConfig
+	payloadConfig.EnableDAGDataset = false
+	payloadConfig.Width = 2
+	payloadConfig.Depth = 3
+	payloadConfig.DAGFanIn = true
+	payloadConfig.DAGPayloadSize = &config.PayloadSizeConfiguration{MeanBytes: 64}
+
+	dag := GenerateDAGs(&payloadConfig, functionList, true)[0]
+
+	joins := 0
+	branches := []*list.List{dag}
+	visited := make(map[*list.List]bool)
+	for len(branches) > 0 {
+		branch := branches[0]
+		branches = branches[1:]
+		if visited[branch] {
+			continue
+		}
+		visited[branch] = true
+
+		for element := branch.Front(); element != nil; element = element.Next() {
+			node := element.Value.(*common.Node)
+			if node.Predecessors > 1 {
+				joins++
+			}
+
+			if isRoot := element == dag.Front(); isRoot != (node.Payload == nil) {
+				t.Errorf("Only the root should not receive data, got %+v at depth %d.", node.Payload, node.Depth)
+			}
+			branches = append(branches, node.Branches...)
+		}
+	}
+
+	if joins != 1 {
+		t.Errorf("Expected the join to be visited once, got %d.", joins)
+	}
+}
+
+func TestSamplePayloadSizes(t *testing.T) {
+	createDAG := func() *list.List {
+		functionList := make([]*common.Function, 7)
+		for i := range functionList {
+			functionList[i] = &common.Function{
+				Name:            fmt.Sprintf("function-%d-%d", i, rand.Int()),
+				InvocationStats: &common.FunctionInvocationStats{HashFunction: fmt.Sprintf("hash-%d", i)},
+				Specification:   &common.FunctionSpecification{RuntimeSpecification: make(common.RuntimeSpecificationArray, 3)},
+			}
+		}
+
+		payloadConfig := *fakeConfig
+		payloadConfig.EnableDAGDataset = false
+		payloadConfig.Width = 2
+		payloadConfig.Depth = 3
+		payloadConfig.DAGFanIn = true
+		payloadConfig.DAGPayloadSize = &config.PayloadSizeConfiguration{Distribution: "exponential", MeanBytes: 1024}
+
+		return GenerateDAGs(&payloadConfig, functionList, true)[0]
+	}
+
+	// the structure of generated DAGs is random, so the sizes are sampled for the same DAG
+	dag := createDAG()
+	sample := func(seed int64) map[string][][]int {
+		SamplePayloadSizes(dag, seed)
+
+		largest := 0
+		result := make(map[string][][]int)
+		for _, element := range dagNodes(dag) {
+			node := element.Value.(*common.Node)
+			if isRoot := element == dag.Front(); isRoot != (node.PayloadSizes == nil) {
+				t.Errorf("Only the root should not receive data, got %v.", node.PayloadSizes)
+			}
+
+			for _, sizes := range node.PayloadSizes {
+				if len(sizes) != max(node.Predecessors, 1) {
+					t.Errorf("Expected a size for each of the %d predecessors of %s, got %v.", node.Predecessors, node.Function.Name, sizes)
+				}
+
+				total := 0
+				for _, size := range sizes {
+					total += size
+				}
+				largest = max(largest, total)
+			}
+
+			result[node.Function.InvocationStats.HashFunction] = node.PayloadSizes
+		}
+
+		if MaxPayloadSize(dag) != largest {
+			t.Errorf("Expected the largest payload to be %d bytes, got %d.", largest, MaxPayloadSize(dag))
+		}
+
+		return result
+	}
+
+	first := sample(42)
+
+	// the names of the functions differ between parses of the trace
+	for _, element := range dagNodes(dag) {
+		element.Value.(*common.Node).Function.Name += "-renamed"
+	}
+
+	if !reflect.DeepEqual(first, sample(42)) {
+		t.Error("Payload sizes of the same seed differ.")
+	}
+	if reflect.DeepEqual(sample(42), sample(43)) {
+		t.Error("Payload sizes do not depend on the seed.")
+	}
+}
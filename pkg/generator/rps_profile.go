@@ -0,0 +1,215 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"encoding/csv"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+// RPSProfile is a time-varying load of the RPS mode, given as the RPS of each second of the experiment
+type RPSProfile struct {
+	rps []float64
+}
+
+// NewRPSProfile creates the load profile of the configuration for the experiment of the given duration in minutes,
+// which includes the warmup:
+//   - ramp linearly goes from RpsStart to RpsTarget over the experiment
+//   - step starts at RpsStart and increases by RpsStepSize every RpsStepSeconds up to RpsTarget
+//   - sine oscillates between RpsStart and RpsTarget with a period of RpsPeriodSeconds, starting at RpsStart
+//   - file reads the RPS from RpsProfileFile, where each point (second, rps) holds until the next one
+func NewRPSProfile(cfg *config.LoaderConfiguration, experimentDuration int) *RPSProfile {
+	seconds := experimentDuration * 60
+	profile := &RPSProfile{rps: make([]float64, seconds)}
+
+	switch strings.ToLower(cfg.RpsProfile) {
+	case "ramp":
+		for i := range profile.rps {
+			// the RPS of a second is taken in its middle
+			profile.rps[i] = cfg.RpsStart + (cfg.RpsTarget-cfg.RpsStart)*(float64(i)+0.5)/float64(seconds)
+		}
+	case "step":
+		if cfg.RpsStepSeconds <= 0 {
+			log.Fatal("RpsStepSeconds of the step RPS profile has to be positive.")
+		}
+
+		for i := range profile.rps {
+			profile.rps[i] = math.Min(cfg.RpsTarget, cfg.RpsStart+float64(i/cfg.RpsStepSeconds)*cfg.RpsStepSize)
+		}
+	case "sine":
+		if cfg.RpsPeriodSeconds <= 0 {
+			log.Fatal("RpsPeriodSeconds of the sine RPS profile has to be positive.")
+		}
+
+		for i := range profile.rps {
+			phase := 2 * math.Pi * (float64(i) + 0.5) / float64(cfg.RpsPeriodSeconds)
+			profile.rps[i] = cfg.RpsStart + (cfg.RpsTarget-cfg.RpsStart)*(1-math.Cos(phase))/2
+		}
+	case "file":
+		profile.readSchedule(cfg.RpsProfileFile)
+	default:
+		log.Fatalf("Unsupported RPS profile %s.", cfg.RpsProfile)
+	}
+
+	for i, rps := range profile.rps {
+		if rps < 0 {
+			log.Fatalf("RPS profile is negative in second %d.", i)
+		}
+	}
+
+	return profile
+}
+
+// readSchedule fills the profile from a CSV file with the header second,rps
+func (p *RPSProfile) readSchedule(path string) {
+	f, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("Failed to open RPS profile file %s - %v", path, err)
+	}
+	defer f.Close()
+
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		log.Fatalf("Failed to read RPS profile file %s - %v", path, err)
+	}
+	if len(records) < 2 {
+		log.Fatalf("RPS profile file %s has no points.", path)
+	}
+
+	previous := -1
+	for i, record := range records[1:] {
+		second, err := strconv.Atoi(strings.TrimSpace(record[0]))
+		common.Check(err)
+		rps, err := strconv.ParseFloat(strings.TrimSpace(record[1]), 64)
+		common.Check(err)
+
+		if second <= previous {
+			log.Fatalf("Seconds of RPS profile file %s have to be increasing (line %d).", path, i+2)
+		}
+		previous = second
+
+		for j := common.MaxOf(second, 0); j < len(p.rps); j++ {
+			p.rps[j] = rps
+		}
+	}
+}
+
+// Scale returns the profile multiplied by the factor, e.g., to split the load into warm and cold starts
+func (p *RPSProfile) Scale(factor float64) *RPSProfile {
+	result := &RPSProfile{rps: make([]float64, len(p.rps))}
+	for i, rps := range p.rps {
+		result.rps[i] = rps * factor
+	}
+
+	return result
+}
+
+// Max returns the peak RPS of the profile
+func (p *RPSProfile) Max() float64 {
+	result := 0.0
+	for _, rps := range p.rps {
+		result = math.Max(result, rps)
+	}
+
+	return result
+}
+
+// arrivals returns the timestamps of invocations in μs, where invocation k is issued once the number of requests the
+// profile asks for since the beginning of the experiment reaches k, so the first one is issued right away
+func (p *RPSProfile) arrivals() []float64 {
+	var result []float64
+
+	requests := 0.0
+	for second, rps := range p.rps {
+		if rps == 0 {
+			continue
+		}
+
+		for next := math.Ceil(requests); next < requests+rps; next++ {
+			result = append(result, (float64(second)+(next-requests)/rps)*common.OneSecondInMicroseconds)
+		}
+		requests += rps
+	}
+
+	return result
+}
+
+// timestampsToIAT converts timestamps into IATs, where the first IAT is the offset of the first invocation
+func timestampsToIAT(timestamps []float64) common.IATArray {
+	result := make(common.IATArray, len(timestamps))
+
+	previous := 0.0
+	for i, timestamp := range timestamps {
+		result[i] = timestamp - previous
+		previous = timestamp
+	}
+
+	return result
+}
+
+// GenerateWarmStartFunctionByProfile generates invocations of a single function following the load profile
+func GenerateWarmStartFunctionByProfile(experimentDuration int, profile *RPSProfile) (common.IATArray, []int) {
+	if profile.Max() == 0 {
+		return nil, countNumberOfInvocationsPerMinute(experimentDuration, nil)
+	}
+
+	iat := timestampsToIAT(profile.arrivals())
+	return iat, countNumberOfInvocationsPerMinute(experimentDuration, iat)
+}
+
+// GenerateColdStartFunctionsByProfile generates invocations following the load profile, which are assigned to
+// functions in a round-robin fashion. There are enough functions for each of them to be idle for at least
+// cooldownSeconds between invocations at the peak RPS of the profile, so every invocation is a cold start.
+func GenerateColdStartFunctionsByProfile(experimentDuration int, profile *RPSProfile, cooldownSeconds int) ([]common.IATArray, [][]int) {
+	arrivals := profile.arrivals()
+	totalFunctions := common.MinOf(int(math.Ceil(profile.Max()*float64(cooldownSeconds))), len(arrivals))
+	if totalFunctions == 0 {
+		return nil, nil
+	}
+
+	timestamps := make([][]float64, totalFunctions)
+	for i, arrival := range arrivals {
+		timestamps[i%totalFunctions] = append(timestamps[i%totalFunctions], arrival)
+	}
+
+	var functions []common.IATArray
+	var countResult [][]int
+	for i := 0; i < totalFunctions; i++ {
+		iat := timestampsToIAT(timestamps[i])
+
+		functions = append(functions, iat)
+		countResult = append(countResult, countNumberOfInvocationsPerMinute(experimentDuration, iat))
+	}
+
+	log.Warn("It is recommended that the first 10% of cold starts are discarded from the experiment results for low cold start RPS.")
+	return functions, countResult
+}
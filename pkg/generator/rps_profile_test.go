@@ -0,0 +1,145 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+func TestRPSProfiles(t *testing.T) {
+	schedule := filepath.Join(t.TempDir(), "rps_profile.csv")
+	if err := os.WriteFile(schedule, []byte("second,rps\n0,1\n60,0\n90,2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		testName      string
+		cfg           config.LoaderConfiguration
+		expectedCount []int
+	}{
+		{
+			testName:      "ramp",
+			cfg:           config.LoaderConfiguration{RpsProfile: "ramp", RpsStart: 0, RpsTarget: 4},
+			expectedCount: []int{30, 90, 150, 210},
+		},
+		{
+			testName:      "step",
+			cfg:           config.LoaderConfiguration{RpsProfile: "step", RpsStart: 1, RpsTarget: 2.5, RpsStepSize: 1, RpsStepSeconds: 60},
+			expectedCount: []int{60, 120, 150, 150},
+		},
+		{
+			testName:      "sine",
+			cfg:           config.LoaderConfiguration{RpsProfile: "sine", RpsStart: 1, RpsTarget: 3, RpsPeriodSeconds: 120},
+			expectedCount: []int{120, 120, 120, 120},
+		},
+		{
+			testName:      "file",
+			cfg:           config.LoaderConfiguration{RpsProfile: "file", RpsProfileFile: schedule},
+			expectedCount: []int{60, 60, 120, 120},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			iat, count := GenerateWarmStartFunctionByProfile(4, NewRPSProfile(&test.cfg, 4))
+
+			if len(count) != 4 {
+				t.Fatalf("Expected counts of 4 minutes, got %v.", count)
+			}
+			for i := range count {
+				if math.Abs(float64(count[i]-test.expectedCount[i])) > 1 {
+					t.Errorf("Expected per-minute count %v, got %v.", test.expectedCount, count)
+				}
+			}
+
+			if iat[0] != 0 {
+				t.Errorf("First invocation should be issued right away, got offset %f.", iat[0])
+			}
+		})
+	}
+}
+
+func TestConstantProfileMatchesRPS(t *testing.T) {
+	cfg := &config.LoaderConfiguration{RpsProfile: "step", RpsStart: 5, RpsTarget: 5, RpsStepSeconds: 1}
+
+	iat, count := GenerateWarmStartFunctionByProfile(2, NewRPSProfile(cfg, 2))
+	expectedIAT, expectedCount := GenerateWarmStartFunction(2, 5)
+	if !reflect.DeepEqual(count, expectedCount) || len(iat) != len(expectedIAT) {
+		t.Fatalf("Expected the same load as with a constant RPS, got counts %v instead of %v.", count, expectedCount)
+	}
+	for i := range iat {
+		if math.Abs(iat[i]-expectedIAT[i]) > 1e-3 {
+			t.Fatalf("Expected the same IATs as with a constant RPS, got %f instead of %f.", iat[i], expectedIAT[i])
+		}
+	}
+
+	functions, _ := GenerateColdStartFunctionsByProfile(1, NewRPSProfile(cfg, 1), 10)
+	expectedFunctions, _ := GenerateColdStartFunctions(1, 5, 10)
+	if len(functions) != len(expectedFunctions) {
+		t.Fatalf("Expected %d cold start functions, got %d.", len(expectedFunctions), len(functions))
+	}
+	for i := range functions {
+		for j := range functions[i] {
+			if math.Abs(functions[i][j]-expectedFunctions[i][j]) > 1e-3 {
+				t.Fatalf("Expected the same IATs as with a constant RPS, got %v instead of %v.", functions[i], expectedFunctions[i])
+			}
+		}
+	}
+}
+
+func TestColdStartFunctionsByProfile(t *testing.T) {
+	cfg := &config.LoaderConfiguration{RpsProfile: "ramp", RpsStart: 0.5, RpsTarget: 4}
+	profile := NewRPSProfile(cfg, 3)
+	cooldownSeconds := 10
+
+	functions, count := GenerateColdStartFunctionsByProfile(3, profile, cooldownSeconds)
+	if len(functions) != int(math.Ceil(profile.Max()*float64(cooldownSeconds))) {
+		t.Errorf("Unexpected number of cold start functions %d.", len(functions))
+	}
+
+	_, warmCount := GenerateWarmStartFunctionByProfile(3, profile)
+	total := make([]int, 3)
+	for i := range functions {
+		for j, value := range functions[i] {
+			if j > 0 && value < float64(cooldownSeconds)*common.OneSecondInMicroseconds-1e-3 {
+				t.Fatalf("Function %d is invoked %f μs after its previous invocation, i.e., within the cooldown.", i, value)
+			}
+		}
+		for minute := range count[i] {
+			total[minute] += count[i][minute]
+		}
+	}
+
+	if !reflect.DeepEqual(total, warmCount) {
+		t.Errorf("Cold start functions should follow the profile, got per-minute count %v instead of %v.", total, warmCount)
+	}
+}
@@ -0,0 +1,91 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"math"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+// SetRuntimeMemoryCorrelation sets the Spearman rank correlation between the runtime and the memory of invocations,
+// which are then drawn through a Gaussian copula. Zero keeps runtime and memory independent.
+func (s *SpecificationGenerator) SetRuntimeMemoryCorrelation(correlation float64) {
+	if correlation < -1 || correlation > 1 {
+		log.Fatalf("Invalid runtime and memory correlation %f.", correlation)
+	}
+
+	s.runtimeMemoryCorrelation = correlation
+}
+
+// determineCorrelatedQuantiles draws quantiles of runtime and memory from a Gaussian copula, whose Pearson
+// correlation 2sin(πρ/6) yields the Spearman rank correlation ρ. Should be called only when specRand is locked.
+func (s *SpecificationGenerator) determineCorrelatedQuantiles() (float64, float64) {
+	pearson := 2 * math.Sin(math.Pi*s.runtimeMemoryCorrelation/6)
+
+	runZ := s.specRand.NormFloat64()
+	memZ := pearson*runZ + math.Sqrt(1-pearson*pearson)*s.specRand.NormFloat64()
+
+	return standardNormalCDF(runZ), standardNormalCDF(memZ)
+}
+
+func standardNormalCDF(z float64) float64 {
+	return 0.5 * math.Erfc(-z/math.Sqrt2)
+}
+
+// interpolateQuantile returns the value of the quantile by linear interpolation between the given percentiles
+func interpolateQuantile(quantile float64, quantiles []float64, values []float64) float64 {
+	i := sort.SearchFloat64s(quantiles, quantile)
+	if i == 0 {
+		return values[0]
+	}
+	if i == len(quantiles) {
+		return values[len(values)-1]
+	}
+
+	fraction := (quantile - quantiles[i-1]) / (quantiles[i] - quantiles[i-1])
+	return values[i-1] + fraction*(values[i]-values[i-1])
+}
+
+// InterpolateRuntime returns the runtime of the quantile by linear interpolation between the percentiles of the trace
+func InterpolateRuntime(runQtl float64, runStats *common.FunctionRuntimeStats) int {
+	return int(interpolateQuantile(runQtl,
+		[]float64{0, 0.01, 0.25, 0.50, 0.75, 0.99, 1},
+		[]float64{runStats.Percentile0, runStats.Percentile1, runStats.Percentile25, runStats.Percentile50,
+			runStats.Percentile75, runStats.Percentile99, runStats.Percentile100},
+	))
+}
+
+// InterpolateMemory returns the memory of the quantile by linear interpolation between the percentiles of the trace,
+// where quantiles below the first percentile are mapped to it
+func InterpolateMemory(memQtl float64, memStats *common.FunctionMemoryStats) int {
+	return int(interpolateQuantile(memQtl,
+		[]float64{0.01, 0.05, 0.25, 0.50, 0.75, 0.95, 0.99, 1},
+		[]float64{memStats.Percentile1, memStats.Percentile5, memStats.Percentile25, memStats.Percentile50,
+			memStats.Percentile75, memStats.Percentile95, memStats.Percentile99, memStats.Percentile100},
+	))
+}
@@ -0,0 +1,97 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"gonum.org/v1/gonum/stat"
+)
+
+func ranks(values []float64) []float64 {
+	indices := make([]int, len(values))
+	for i := range indices {
+		indices[i] = i
+	}
+	sort.SliceStable(indices, func(a, b int) bool { return values[indices[a]] < values[indices[b]] })
+
+	result := make([]float64, len(values))
+	for rank, i := range indices {
+		result[i] = float64(rank)
+	}
+
+	return result
+}
+
+func TestInterpolatedExecutionSpecs(t *testing.T) {
+	if runtime := InterpolateRuntime(0.625, testFunction.RuntimeStats); runtime != 62 {
+		t.Errorf("Expected runtime 62 at quantile 0.625, got %d.", runtime)
+	}
+	if memory := InterpolateMemory(0.005, testFunction.MemoryStats); memory != 100 {
+		t.Errorf("Expected memory of the first percentile below it, got %d.", memory)
+	}
+	if memory := InterpolateMemory(0.97, testFunction.MemoryStats); memory != 9700 {
+		t.Errorf("Expected memory 9700 at quantile 0.97, got %d.", memory)
+	}
+}
+
+func TestRuntimeMemoryCorrelation(t *testing.T) {
+	function := testFunction
+	function.RuntimeStats = &common.FunctionRuntimeStats{
+		Count: 100, Percentile0: 10, Percentile1: 100, Percentile25: 2500, Percentile50: 5000, Percentile75: 7500,
+		Percentile99: 9900, Percentile100: 10000,
+	}
+
+	for _, correlation := range []float64{-0.8, 0.3, 0.9} {
+		sg := NewSpecificationGenerator(42)
+		sg.SetRuntimeMemoryCorrelation(correlation)
+
+		runtimes, memories := make([]float64, 20_000), make([]float64, 20_000)
+		for i := range runtimes {
+			spec := sg.generateExecutionSpecs(&function)
+			runtimes[i], memories[i] = float64(spec.Runtime), float64(spec.Memory)
+		}
+
+		spearman := stat.Correlation(ranks(runtimes), ranks(memories), nil)
+		if math.Abs(spearman-correlation) > 0.03 {
+			t.Errorf("Expected rank correlation %f, got %f.", correlation, spearman)
+		}
+
+		// the marginal distributions still follow the percentiles of the trace
+		if median := stat.Quantile(0.5, stat.Empirical, sortedCopy(runtimes), nil); math.Abs(median-5000) > 150 {
+			t.Errorf("Expected median runtime around 5000, got %f.", median)
+		}
+	}
+}
+
+func sortedCopy(values []float64) []float64 {
+	result := append([]float64{}, values...)
+	sort.Float64s(result)
+
+	return result
+}
@@ -25,6 +25,8 @@
 package generator
 
 import (
+	"encoding/binary"
+	"hash/fnv"
 	"math/rand"
 
 	log "github.com/sirupsen/logrus"
@@ -32,15 +34,63 @@ import (
 )
 
 type SpecificationGenerator struct {
+	// global seed, from which the random sources are reseeded for each function
+	seed     int64
 	iatRand  *rand.Rand
 	specRand *rand.Rand
+
+	// arrival model of functions by their trigger
+	arrivalModels map[string]ArrivalModel
+	// shape of the IAT distribution, zero for the default one
+	iatShape float64
+	// IAT CDFs of the empirical distribution by HashFunction and the one of the function being generated
+	empiricalCDFs map[string]*EmpiricalCDF
+	iatCDF        *EmpiricalCDF
+	// state of the MMPP chain of the function being generated
+	mmppState int
+	// rank correlation between runtime and memory of invocations
+	runtimeMemoryCorrelation float64
+	// burst events shared by all functions and the ones of the function being generated
+	burstEvents    []BurstEvent
+	functionBursts []BurstEvent
 }
 
 func NewSpecificationGenerator(seed int64) *SpecificationGenerator {
 	return &SpecificationGenerator{
+		seed:     seed,
 		iatRand:  rand.New(rand.NewSource(seed)),
 		specRand: rand.New(rand.NewSource(seed)),
+
+		arrivalModels: make(map[string]ArrivalModel),
+	}
+}
+
+// functionIdentity identifies the function by the hashes of its trace row, as its name depends on its position in the
+// trace. Functions without hashes, e.g., of synthetic traces, are identified by their name.
+func functionIdentity(function *common.Function) string {
+	if stats := function.InvocationStats; stats != nil && stats.HashOwner+stats.HashApp+stats.HashFunction != "" {
+		return stats.HashOwner + "/" + stats.HashApp + "/" + stats.HashFunction
 	}
+
+	return function.Name
+}
+
+// functionSeed derives the seed of a function from the global seed and its identity
+func functionSeed(seed int64, identity string) int64 {
+	hash := fnv.New64a()
+	_ = binary.Write(hash, binary.LittleEndian, seed)
+	_, _ = hash.Write([]byte(identity))
+
+	return int64(hash.Sum64())
+}
+
+// seedFunction reseeds the random sources for the function with the given identity, so that its specification only
+// depends on its own trace row and not on which functions were generated before it
+func (s *SpecificationGenerator) seedFunction(identity string) {
+	seed := functionSeed(s.seed, identity)
+
+	s.iatRand = rand.New(rand.NewSource(seed))
+	s.specRand = rand.New(rand.NewSource(seed))
 }
 
 //////////////////////////////////////////////////
@@ -66,6 +116,16 @@ func (s *SpecificationGenerator) generateIATPerGranularity(numberOfInvocations i
 			iat = s.iatRand.ExpFloat64()
 		case common.Uniform:
 			iat = s.iatRand.Float64()
+		case common.Gamma:
+			iat = sampleGamma(s.iatRand, s.shapeOf(iatDistribution))
+		case common.Weibull:
+			iat = sampleWeibull(s.iatRand, s.shapeOf(iatDistribution))
+		case common.Lognormal:
+			iat = sampleLognormal(s.iatRand, s.shapeOf(iatDistribution))
+		case common.Pareto:
+			iat = samplePareto(s.iatRand, s.shapeOf(iatDistribution))
+		case common.Empirical:
+			iat = sampleEmpirical(s.iatRand, s.iatCDF)
 		case common.Equidistant:
 			equalDistance := common.OneSecondInMicroseconds / float64(numberOfInvocations)
 			if granularity == common.MinuteGranularity {
@@ -90,9 +150,10 @@ func (s *SpecificationGenerator) generateIATPerGranularity(numberOfInvocations i
 		totalDuration = 1
 	}
 
-	if iatDistribution == common.Uniform || iatDistribution == common.Exponential {
+	if iatDistribution != common.Equidistant {
 		// Uniform: 		we need to scale IAT from [0, 1) to [0, 60 seconds)
 		// Exponential: 	we need to scale IAT from [0, +MaxFloat64) to [0, 60 seconds)
+		// Gamma, Weibull, lognormal, Pareto and empirical are scaled the same way as exponential
 		for i := 0; i < len(iatResult); i++ {
 			// how much does the IAT contributes to the total IAT sum
 			iatResult[i] = iatResult[i] / totalDuration
@@ -140,7 +201,7 @@ func getBlankTimeUnit(granularity common.TraceGranularity) float64 {
 }
 
 // GenerateIAT generates IAT according to the given distribution. Number of minutes is the length of invocationsPerMinute array
-func (s *SpecificationGenerator) generateIAT(invocationsPerMinute []int, iatDistribution common.IatDistribution,
+func (s *SpecificationGenerator) generateIAT(invocationsPerMinute []int, model ArrivalModel, iatDistribution common.IatDistribution,
 	shiftIAT bool, granularity common.TraceGranularity) (common.IATArray, []int, common.ProbabilisticDuration) {
 
 	var IAT = []float64{0.0}
@@ -149,7 +210,7 @@ func (s *SpecificationGenerator) generateIAT(invocationsPerMinute []int, iatDist
 
 	numberOfMinutes := len(invocationsPerMinute)
 	for i := 0; i < numberOfMinutes; i++ {
-		minuteIAT, duration := s.generateIATPerGranularity(invocationsPerMinute[i], iatDistribution, shiftIAT, granularity)
+		minuteIAT, duration := s.generateBurstyIATPerTimeUnit(i, invocationsPerMinute[i], model, iatDistribution, shiftIAT, granularity)
 
 		IAT[len(IAT)-1] += minuteIAT[0]
 		IAT = append(IAT, minuteIAT[1:]...)
@@ -163,10 +224,23 @@ func (s *SpecificationGenerator) generateIAT(invocationsPerMinute []int, iatDist
 }
 
 func (s *SpecificationGenerator) GenerateInvocationData(function *common.Function, iatDistribution common.IatDistribution, shiftIAT bool, granularity common.TraceGranularity) *common.FunctionSpecification {
+	return s.generateInvocationData(function, functionIdentity(function), iatDistribution, shiftIAT, granularity)
+}
+
+func (s *SpecificationGenerator) generateInvocationData(function *common.Function, identity string, iatDistribution common.IatDistribution, shiftIAT bool, granularity common.TraceGranularity) *common.FunctionSpecification {
 	invocationsPerMinute := function.InvocationStats.Invocations
+	s.seedFunction(identity)
+	s.selectEmpiricalCDF(function, iatDistribution)
+	s.mmppState = 0
+	s.selectBurstEvents(identity)
 
 	// Generating IAT
-	iat, perMinuteCount, rawDuration := s.generateIAT(invocationsPerMinute, iatDistribution, shiftIAT, granularity)
+	model := s.arrivalModel(function, granularity)
+	iat, perMinuteCount, rawDuration := s.generateIAT(invocationsPerMinute, model, iatDistribution, shiftIAT, granularity)
+	if model.Type == MMPPArrivals {
+		log.Infof("Index of dispersion of MMPP arrivals of function %s: %.3f", function.Name,
+			IndexOfDispersion(iat, getBlankTimeUnit(granularity)/60))
+	}
 
 	// Generating runtime specifications
 	var runtimeArray common.RuntimeSpecificationArray
@@ -264,9 +338,18 @@ func (s *SpecificationGenerator) generateExecutionSpecs(function *common.Functio
 		log.Fatal("Invalid duration or memory specification of the function '" + function.Name + "'.")
 	}
 
-	runQtl, memQtl := s.determineExecutionSpecSeedQuantiles()
-	runtime := common.MinOf(common.MaxExecTimeMilli, common.MaxOf(common.MinExecTimeMilli, GenerateExecuteSpec(s.specRand, runQtl, runStats)))
-	memory := common.MinOf(common.MaxMemQuotaMib, common.MaxOf(common.MinMemQuotaMib, GenerateMemorySpec(s.specRand, memQtl, memStats)))
+	var runtime, memory int
+	if s.runtimeMemoryCorrelation != 0 {
+		// a second draw within the percentiles would weaken the correlation of the quantiles
+		runQtl, memQtl := s.determineCorrelatedQuantiles()
+		runtime, memory = InterpolateRuntime(runQtl, runStats), InterpolateMemory(memQtl, memStats)
+	} else {
+		runQtl, memQtl := s.determineExecutionSpecSeedQuantiles()
+		runtime, memory = GenerateExecuteSpec(s.specRand, runQtl, runStats), GenerateMemorySpec(s.specRand, memQtl, memStats)
+	}
+
+	runtime = common.MinOf(common.MaxExecTimeMilli, common.MaxOf(common.MinExecTimeMilli, runtime))
+	memory = common.MinOf(common.MaxMemQuotaMib, common.MaxOf(common.MinMemQuotaMib, memory))
 
 	return common.RuntimeSpecification{
 		Runtime: runtime,
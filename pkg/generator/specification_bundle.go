@@ -0,0 +1,282 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+const (
+	DefaultSpecificationPath = "specification"
+
+	specificationBundleVersion = 1
+	specificationManifestFile  = "manifest.json"
+)
+
+// SpecificationManifest describes the trace and configuration the specifications of a bundle were generated from, so
+// that they are only ever reused for the same ones
+type SpecificationManifest struct {
+	Version int `json:"Version"`
+
+	TraceHash       string `json:"TraceHash"`
+	ConfigHash      string `json:"ConfigHash"`
+	Seed            int64  `json:"Seed"`
+	IATDistribution string `json:"IATDistribution"`
+	Granularity     string `json:"Granularity"`
+	TraceDuration   int    `json:"TraceDuration"`
+
+	Functions []SpecificationBundleEntry `json:"Functions"`
+}
+
+// SpecificationBundleEntry is a function of a bundle with the hash of its trace row and the file of its specification
+type SpecificationBundleEntry struct {
+	Name      string `json:"Name"`
+	TraceHash string `json:"TraceHash"`
+	File      string `json:"File"`
+}
+
+func hashJSON(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		// the hashed types are plain data, which is always marshalled
+		panic(err)
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// functionTraceHash hashes the row of the function in the trace, but not its name, which is not always stable
+func functionTraceHash(function *common.Function) string {
+	return hashJSON(struct {
+		InvocationStats *common.FunctionInvocationStats
+		RuntimeStats    *common.FunctionRuntimeStats
+		MemoryStats     *common.FunctionMemoryStats
+	}{function.InvocationStats, function.RuntimeStats, function.MemoryStats})
+}
+
+// generationConfigHash hashes the parameters of the configuration the specifications depend on besides the ones kept
+// in the manifest as they are
+func generationConfigHash(cfg *config.LoaderConfiguration) (string, error) {
+	var cdfHash string
+	if cfg.IATCDFPath != "" {
+		data, err := os.ReadFile(cfg.IATCDFPath)
+		if err != nil {
+			return "", fmt.Errorf("failed to read the IAT CDF - %w", err)
+		}
+
+		sum := sha256.Sum256(data)
+		cdfHash = hex.EncodeToString(sum[:])
+	}
+
+	return hashJSON(struct {
+		IATShape                 float64
+		IATCDF                   string
+		RuntimeMemoryCorrelation float64
+		TriggerArrivalModels     map[string]config.ArrivalModelConfiguration
+		BurstEvents              []config.BurstEventConfiguration
+	}{cfg.IATShape, cdfHash, cfg.RuntimeMemoryCorrelation, cfg.TriggerArrivalModels, cfg.BurstEvents}), nil
+}
+
+// NewSpecificationManifest describes the specifications generated for the functions of the configuration
+func NewSpecificationManifest(cfg *config.Configuration) (*SpecificationManifest, error) {
+	configHash, err := generationConfigHash(cfg.LoaderConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &SpecificationManifest{
+		Version:         specificationBundleVersion,
+		ConfigHash:      configHash,
+		Seed:            cfg.LoaderConfiguration.Seed,
+		IATDistribution: cfg.LoaderConfiguration.IATDistribution,
+		Granularity:     cfg.LoaderConfiguration.Granularity,
+		TraceDuration:   cfg.TraceDuration,
+	}
+
+	var rowHashes []string
+	for i, function := range cfg.Functions {
+		entry := SpecificationBundleEntry{
+			Name:      function.Name,
+			TraceHash: functionTraceHash(function),
+			File:      fmt.Sprintf("function%d.json.gz", i),
+		}
+
+		manifest.Functions = append(manifest.Functions, entry)
+		rowHashes = append(rowHashes, entry.TraceHash)
+	}
+	manifest.TraceHash = hashJSON(rowHashes)
+
+	return manifest, nil
+}
+
+// mismatch returns what differs between the manifest of a bundle and the expected one, or an empty string if the
+// bundle can be used
+func (m *SpecificationManifest) mismatch(expected *SpecificationManifest) string {
+	switch {
+	case m.Version != expected.Version:
+		return fmt.Sprintf("version %d instead of %d", m.Version, expected.Version)
+	case m.Seed != expected.Seed:
+		return fmt.Sprintf("seed %d instead of %d", m.Seed, expected.Seed)
+	case m.IATDistribution != expected.IATDistribution:
+		return fmt.Sprintf("IAT distribution %s instead of %s", m.IATDistribution, expected.IATDistribution)
+	case m.Granularity != expected.Granularity:
+		return fmt.Sprintf("granularity %s instead of %s", m.Granularity, expected.Granularity)
+	case m.TraceDuration != expected.TraceDuration:
+		return fmt.Sprintf("trace duration %d instead of %d", m.TraceDuration, expected.TraceDuration)
+	case m.ConfigHash != expected.ConfigHash:
+		return "different generation parameters"
+	case len(m.Functions) != len(expected.Functions):
+		return fmt.Sprintf("%d functions instead of %d", len(m.Functions), len(expected.Functions))
+	}
+
+	for i, function := range m.Functions {
+		if function.TraceHash != expected.Functions[i].TraceHash {
+			return fmt.Sprintf("different trace row of function %d (%s instead of %s)", i, function.Name, expected.Functions[i].Name)
+		}
+	}
+
+	if m.TraceHash != expected.TraceHash {
+		return "different trace"
+	}
+
+	return ""
+}
+
+// WriteSpecificationBundle writes the specifications of the functions of the configuration as gzipped JSON files into
+// the directory, along with the manifest describing where they come from
+func WriteSpecificationBundle(path string, cfg *config.Configuration) error {
+	manifest, err := NewSpecificationManifest(cfg)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(path, 0755); err != nil {
+		return err
+	}
+	// the manifest of a bundle being overwritten is removed first and the new one is written last, so that an
+	// interrupted write does not leave a bundle behind that looks complete
+	if err = os.Remove(filepath.Join(path, specificationManifestFile)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	for i, function := range cfg.Functions {
+		if err := writeGzipJSON(filepath.Join(path, manifest.Functions[i].File), function.Specification); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.MarshalIndent(manifest, "", " ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(path, specificationManifestFile), data, 0644)
+}
+
+// ReadSpecificationBundle reads the specifications of the functions of the configuration from the directory, provided
+// that the bundle was generated from the same trace and configuration
+func ReadSpecificationBundle(path string, cfg *config.Configuration) error {
+	data, err := os.ReadFile(filepath.Join(path, specificationManifestFile))
+	if err != nil {
+		return err
+	}
+
+	var manifest SpecificationManifest
+	if err = json.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("malformed manifest - %w", err)
+	}
+
+	expected, err := NewSpecificationManifest(cfg)
+	if err != nil {
+		return err
+	}
+
+	if mismatch := manifest.mismatch(expected); mismatch != "" {
+		return fmt.Errorf("bundle does not match the current trace and configuration: %s", mismatch)
+	}
+
+	specifications := make([]*common.FunctionSpecification, len(manifest.Functions))
+	for i, entry := range manifest.Functions {
+		specifications[i] = &common.FunctionSpecification{}
+		if err = readGzipJSON(filepath.Join(path, entry.File), specifications[i]); err != nil {
+			return err
+		}
+	}
+
+	// functions are only updated once the whole bundle has been read
+	for i, function := range cfg.Functions {
+		function.Specification = specifications[i]
+	}
+
+	return nil
+}
+
+func writeGzipJSON(path string, value interface{}) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := gzip.NewWriter(file)
+	if err = json.NewEncoder(writer).Encode(value); err != nil {
+		return err
+	}
+	if err = writer.Close(); err != nil {
+		return err
+	}
+
+	return file.Close()
+}
+
+func readGzipJSON(path string, value interface{}) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("%s - %w", path, err)
+	}
+	defer reader.Close()
+
+	if err = json.NewDecoder(reader).Decode(value); err != nil {
+		return fmt.Errorf("%s - %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,169 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+func createBundleConfiguration() *config.Configuration {
+	var functions []*common.Function
+	for _, name := range []string{"f1", "f2"} {
+		functions = append(functions, &common.Function{
+			Name:            name,
+			InvocationStats: &common.FunctionInvocationStats{HashFunction: name, Invocations: []int{2}},
+			RuntimeStats:    &common.FunctionRuntimeStats{HashFunction: name, Average: 10},
+			MemoryStats:     &common.FunctionMemoryStats{HashFunction: name, Percentile100: 128},
+			Specification: &common.FunctionSpecification{
+				IAT:            []float64{1_000, 59_999_000},
+				PerMinuteCount: []int{2},
+				RuntimeSpecification: []common.RuntimeSpecification{
+					{Runtime: 10, Memory: 128},
+					{Runtime: 12, Memory: 64},
+				},
+			},
+		})
+	}
+
+	return &config.Configuration{
+		LoaderConfiguration: &config.LoaderConfiguration{
+			Seed:            42,
+			IATDistribution: "exponential",
+			Granularity:     "minute",
+		},
+		TraceDuration: 1,
+		Functions:     functions,
+	}
+}
+
+func TestSpecificationBundle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bundle")
+
+	cfg := createBundleConfiguration()
+	if err := WriteSpecificationBundle(path, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := createBundleConfiguration()
+	for _, function := range loaded.Functions {
+		function.Specification = nil
+	}
+	if err := ReadSpecificationBundle(path, loaded); err != nil {
+		t.Fatal(err)
+	}
+	for i, function := range loaded.Functions {
+		if !reflect.DeepEqual(function.Specification, cfg.Functions[i].Specification) {
+			t.Errorf("Expected specification %v of %s, got %v.", cfg.Functions[i].Specification, function.Name, function.Specification)
+		}
+	}
+
+	tests := []struct {
+		testName string
+		modify   func(cfg *config.Configuration)
+		expected string
+	}{
+		{testName: "seed", modify: func(cfg *config.Configuration) { cfg.LoaderConfiguration.Seed = 7 }, expected: "seed"},
+		{testName: "distribution", modify: func(cfg *config.Configuration) { cfg.LoaderConfiguration.IATDistribution = "uniform" }, expected: "IAT distribution"},
+		{testName: "granularity", modify: func(cfg *config.Configuration) { cfg.LoaderConfiguration.Granularity = "second" }, expected: "granularity"},
+		{testName: "duration", modify: func(cfg *config.Configuration) { cfg.TraceDuration = 2 }, expected: "trace duration"},
+		{testName: "generation_parameters", modify: func(cfg *config.Configuration) { cfg.LoaderConfiguration.IATShape = 2 }, expected: "generation parameters"},
+		{testName: "removed_function", modify: func(cfg *config.Configuration) { cfg.Functions = cfg.Functions[:1] }, expected: "2 functions instead of 1"},
+		{testName: "trace_row", modify: func(cfg *config.Configuration) { cfg.Functions[1].InvocationStats.Invocations[0] = 3 }, expected: "trace row of function 1"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			stale := createBundleConfiguration()
+			test.modify(stale)
+
+			err := ReadSpecificationBundle(path, stale)
+			if err == nil || !strings.Contains(err.Error(), test.expected) {
+				t.Errorf("Expected a mismatch of the %s, got %v.", test.expected, err)
+			}
+			if stale.Functions[0].Specification.RuntimeSpecification[1].Runtime != 12 {
+				t.Error("Functions were modified by a stale bundle.")
+			}
+		})
+	}
+}
+
+func TestSpecificationBundleCorrupted(t *testing.T) {
+	path := t.TempDir()
+
+	cfg := createBundleConfiguration()
+	if err := ReadSpecificationBundle(path, cfg); err == nil {
+		t.Error("Expected an error for a missing bundle.")
+	}
+
+	if err := WriteSpecificationBundle(path, cfg); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(path, "function1.json.gz"), []byte("not gzip"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ReadSpecificationBundle(path, createBundleConfiguration()); err == nil {
+		t.Error("Expected an error for a corrupted specification file.")
+	}
+}
+
+func TestSpecificationBundleInterruptedOverwrite(t *testing.T) {
+	path := t.TempDir()
+
+	if err := WriteSpecificationBundle(path, createBundleConfiguration()); err != nil {
+		t.Fatal(err)
+	}
+
+	// the bundle is overwritten with another seed and a third function, whose specification cannot be written after
+	// the ones of the first two functions have been replaced
+	overwrite := createBundleConfiguration()
+	overwrite.LoaderConfiguration.Seed = 7
+	overwrite.Functions = append(overwrite.Functions, overwrite.Functions[0])
+	if err := os.Mkdir(filepath.Join(path, "function2.json.gz"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteSpecificationBundle(path, overwrite); err == nil {
+		t.Fatal("Expected the write of the bundle to fail.")
+	}
+
+	if err := ReadSpecificationBundle(path, createBundleConfiguration()); err == nil {
+		t.Error("Expected an error for a partially overwritten bundle.")
+	}
+}
+
+func TestSpecificationBundleMissingCDF(t *testing.T) {
+	cfg := createBundleConfiguration()
+	cfg.LoaderConfiguration.IATCDFPath = filepath.Join(t.TempDir(), "missing.csv")
+
+	if err := WriteSpecificationBundle(t.TempDir(), cfg); err == nil || !strings.Contains(err.Error(), "IAT CDF") {
+		t.Errorf("Expected an error for a missing IAT CDF, got %v.", err)
+	}
+}
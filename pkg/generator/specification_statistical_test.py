@@ -44,6 +44,21 @@ elif distribution == "exponential":
         f[i] = f[i] / 60_000_000 * totalDuration
 
     cdf = stats.expon.cdf
+elif distribution in ["gamma", "weibull", "lognormal", "pareto"]:
+    # undo the normalization to the time unit to recover the samples of the distribution with unit scale
+    totalDuration = float(sys.argv[3])
+    shape = float(sys.argv[4])
+    for i in range(len(f)):
+        f[i] = f[i] / 60_000_000 * totalDuration
+
+    if distribution == "gamma":
+        cdf = stats.gamma(a=shape).cdf
+    elif distribution == "weibull":
+        cdf = stats.weibull_min(c=shape).cdf
+    elif distribution == "lognormal":
+        cdf = stats.lognorm(s=shape).cdf
+    else:
+        cdf = stats.pareto(b=shape).cdf
 else:
     exit(2)  # unsupported distribution
 
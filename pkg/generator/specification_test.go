@@ -29,6 +29,7 @@ import (
 	"math"
 	"os"
 	"os/exec"
+	"reflect"
 	"strconv"
 	"sync"
 	"testing"
@@ -176,10 +177,10 @@ func TestSerialGenerateIAT(t *testing.T) {
 			granularity:     common.MinuteGranularity,
 			expectedPoints: []float64{
 				0,
-				10_915_517.87088835,
-				30_667_196.933948774,
-				13_532_618.079060797,
-				4_629_211.062276573,
+				42_764_693.04637169,
+				8_173_126.163709093,
+				1_473_181.417668516,
+				6_789_885.487935763,
 			},
 			testDistribution: false,
 		},
@@ -190,11 +191,11 @@ func TestSerialGenerateIAT(t *testing.T) {
 			shiftIAT:        true,
 			granularity:     common.MinuteGranularity,
 			expectedPoints: []float64{
-				98_328.72677667439,
-				255_456.05382550636,
-				10_915_517.87088835,
-				30_667_196.933948774,
-				13_532_618.079060797,
+				35_845_635.06691544,
+				8_173_126.163709093,
+				1_473_181.417668516,
+				6_789_885.487935763,
+				799_113.8843149333,
 			},
 			testDistribution: false,
 		},
@@ -214,7 +215,7 @@ func TestSerialGenerateIAT(t *testing.T) {
 			shiftIAT:        true,
 			granularity:     common.MinuteGranularity,
 			expectedPoints: []float64{
-				11_689_078.788397402,
+				30_045_889.646830827,
 			},
 			testDistribution: false,
 		},
@@ -225,9 +226,9 @@ func TestSerialGenerateIAT(t *testing.T) {
 			shiftIAT:        true,
 			granularity:     common.MinuteGranularity,
 			expectedPoints: []float64{
-				13_794_305.088992357,
-				14_731_963.725122724,
-				11_882_919.652012857,
+				1_945_956.0454016924,
+				1_686_494.8961365928,
+				48_956_927.97408225,
 			},
 			testDistribution: false,
 		},
@@ -289,6 +290,42 @@ func TestSerialGenerateIAT(t *testing.T) {
 			expectedPoints:   nil,
 			testDistribution: true,
 		},
+		{
+			testName:         "1000000inv_1min_gamma",
+			invocations:      []int{1000000},
+			iatDistribution:  common.Gamma,
+			shiftIAT:         false,
+			granularity:      common.MinuteGranularity,
+			expectedPoints:   nil,
+			testDistribution: true,
+		},
+		{
+			testName:         "1000000inv_1min_weibull",
+			invocations:      []int{1000000},
+			iatDistribution:  common.Weibull,
+			shiftIAT:         false,
+			granularity:      common.MinuteGranularity,
+			expectedPoints:   nil,
+			testDistribution: true,
+		},
+		{
+			testName:         "1000000inv_1min_lognormal",
+			invocations:      []int{1000000},
+			iatDistribution:  common.Lognormal,
+			shiftIAT:         false,
+			granularity:      common.MinuteGranularity,
+			expectedPoints:   nil,
+			testDistribution: true,
+		},
+		{
+			testName:         "1000000inv_1min_pareto",
+			invocations:      []int{1000000},
+			iatDistribution:  common.Pareto,
+			shiftIAT:         false,
+			granularity:      common.MinuteGranularity,
+			expectedPoints:   nil,
+			testDistribution: true,
+		},
 		{
 			testName:        "11inv_3min_equidistant",
 			invocations:     []int{5, 4, 2},
@@ -435,18 +472,18 @@ func TestSerialGenerateIAT(t *testing.T) {
 			granularity:     common.MinuteGranularity,
 			expectedPoints: []float64{
 				// minute 1
-				60_000_000,           // 1min 0s
-				10_915_517.87088835,  // 1min 10.915s
-				30_667_196.933948774, // 1min 41.582s
-				13_532_618.079060797, // 1min 55.115s
-				4_629_211.062276573,  // 1min 59.744s
+				60_000_000,          // 1min 0s
+				42_764_693.04637169, // 1min 42.765s
+				8_173_126.163709093, // 1min 50.938s
+				1_473_181.417668516, // 1min 52.411s
+				6_789_885.487935763, // 1min 59.201s
 				// minute 2
-				255_456.05382550636,  // 2min
-				22_585_831.064472314, // 2min 22.585s
-				11_847_735.687921358, // 2min 34.436s
-				8_436_481.064108288,  // 3min 42.870s
+				799_113.8843149333,   // 2min
+				5_044_833.459359174,  // 2min 5.045s
+				22_063_156.663341463, // 2min 27.108s
+				17_585_682.74728413,  // 2min 44.694s
 				// minute 5
-				137_129_952.18349802, // 5min
+				135_306_327.13001525, // 5min
 				// minute 9
 				240_000_000, // 9min
 			},
@@ -518,17 +555,17 @@ func TestSerialGenerateIAT(t *testing.T) {
 			granularity:     common.MinuteGranularity,
 			expectedPoints: []float64{
 				// minute 1
-				60_000_000,           // 1min
-				15_750_079.698430749, // 1min 15.750s
+				60_000_000,          // 1min
+				50_372_819.69610713, // 1min 50.373s
 				// minute 3
-				104_249_920.30156925, // 3min
-				44_706_790.18202999,  // 3min 44.706s
+				69_627_180.30389287,  // 3min
+				10_697_103.880420163, // 3min 10.697s
 				// minute 6
-				135_293_209.81797, // 7min
-				458_336.491206043, // 7min 0.458s
+				169_302_896.11957985, // 6min
+				7_825_749.060250894,  // 6min 7.826s
 				// minute 10
-				239_541_663.50879395, // 10min
-				35_045_185.62217357,  // 10min 35.045s
+				232_174_250.93974912, // 10min
+				33_387_847.399279043, // 10min 33.388s
 			},
 			testDistribution: false,
 		},
@@ -565,20 +602,20 @@ func TestSerialGenerateIAT(t *testing.T) {
 			granularity:     common.MinuteGranularity,
 			expectedPoints: []float64{
 				// minute 2
-				120_000_000,          // 2min 0s
-				10_962_190.503008047, // 2min 10.962s
-				30_798_323.905437488, // 2min 41.760s
-				13_590_480.922829745, // 2min 55.350s
+				120_000_000,         // 2min 0s
+				43_341_945.55413049, // 2min 43.342s
+				8_283_449.82648189,  // 2min 51.625s
+				1_493_066.926183933, // 2min 53.118s
 				// minute 4
-				64_649_004.668724716, // 3min 0s
+				66_881_537.69320369, // 4min 0s
 				// minute 5
 				60_000_000,           // 5min 0s
-				18_462_616.56871746,  // 5min 18.463s
-				9_684_841.819156349,  // 5min 28.328s
-				6_896_337.559209308,  // 5min 35.224s
-				14_002_749.693008821, // 5min 49.226s
+				5_039_887.978015123,  // 5min 5.040s
+				22_041_528.01483435,  // 5min 27.081s
+				17_568_443.394062713, // 5min 44.650s
+				15_291_322.243158761, // 5min 59.941s
 				// minute 9
-				190_953_454.35990804, // 9min 0s
+				180_058_818.36992905, // 9min 0s
 			},
 			testDistribution: false,
 		},
@@ -632,7 +669,7 @@ func TestSerialGenerateIAT(t *testing.T) {
 			}
 
 			if test.testDistribution && test.iatDistribution != common.Equidistant &&
-				!checkDistribution(IAT, perMinuteCount, nonScaledDuration, test.iatDistribution) {
+				!checkDistribution(t, IAT, perMinuteCount, nonScaledDuration, test.iatDistribution) {
 
 				t.Error("The provided sample does not satisfy the given distribution.")
 			}
@@ -672,7 +709,12 @@ func TestSerialGenerateIAT(t *testing.T) {
 	return false
 }*/
 
-func checkDistribution(data []float64, perMinuteCount []int, nonScaledDuration []float64, distribution common.IatDistribution) bool {
+func checkDistribution(t *testing.T, data []float64, perMinuteCount []int, nonScaledDuration []float64, distribution common.IatDistribution) bool {
+	// the statistical test cannot reject the sample without its Python dependencies
+	if err := exec.Command("python3", "-c", "import matplotlib, numpy, scipy").Run(); err != nil {
+		t.Skipf("Skipping the statistical test, as its Python dependencies cannot be imported: %v", err)
+	}
+
 	// PREPARING ARGUMENTS
 	var dist string
 	inputFile := "test_data.txt"
@@ -682,6 +724,14 @@ func checkDistribution(data []float64, perMinuteCount []int, nonScaledDuration [
 		dist = "uniform"
 	case common.Exponential:
 		dist = "exponential"
+	case common.Gamma:
+		dist = "gamma"
+	case common.Weibull:
+		dist = "weibull"
+	case common.Lognormal:
+		dist = "lognormal"
+	case common.Pareto:
+		dist = "pareto"
 	default:
 		log.Fatal("Unsupported distribution check")
 	}
@@ -710,23 +760,22 @@ func checkDistribution(data []float64, perMinuteCount []int, nonScaledDuration [
 		}
 
 		// SETTING UP THE TESTING SCRIPT
-		args := []string{"specification_statistical_test.py", dist, inputFile, fmt.Sprintf("%f", nonScaledDuration[min])}
+		args := []string{"specification_statistical_test.py", dist, inputFile, fmt.Sprintf("%f", nonScaledDuration[min]),
+			fmt.Sprintf("%f", defaultIATShapes[distribution])}
 		statisticalTest := exec.Command("python3", args...)
 
 		// CALLING THE TESTING SCRIPT AND PROCESSING ITS RESULTS
 		// NOTE: the script generates a histogram in PNG format that can be used as a sanity-check
-		if err := statisticalTest.Wait(); err != nil {
-			output, _ := statisticalTest.Output()
-			log.Debug(string(output))
-
-			switch statisticalTest.ProcessState.ExitCode() {
-			case 0:
-				result = true // distribution satisfied
-			case 1:
-				return false // distribution not satisfied
-			case 2:
-				log.Fatal("Unsupported distribution by the statistical test.")
-			}
+		output, _ := statisticalTest.CombinedOutput()
+		log.Debug(string(output))
+
+		switch statisticalTest.ProcessState.ExitCode() {
+		case 0:
+			result = true // distribution satisfied
+		case 2:
+			log.Fatal("Unsupported distribution by the statistical test.")
+		default:
+			return false // distribution not satisfied
 		}
 	}
 
@@ -836,3 +885,66 @@ func TestGenerateExecutionSpecifications(t *testing.T) {
 		})
 	}
 }
+
+func TestSpecificationIndependentOfFunctionOrder(t *testing.T) {
+	createFunction := func(hash string, invocations []int) *common.Function {
+		function := testFunction
+		function.Name = "function-" + hash
+		function.InvocationStats = &common.FunctionInvocationStats{HashFunction: hash, Invocations: invocations}
+
+		return &function
+	}
+
+	functions := []*common.Function{
+		createFunction("f1", []int{5, 2, 7}),
+		createFunction("f2", []int{3, 0, 9}),
+		createFunction("f3", []int{5, 2, 7}),
+	}
+
+	generate := func(order []int) map[string]*common.FunctionSpecification {
+		sg := NewSpecificationGenerator(42)
+
+		result := make(map[string]*common.FunctionSpecification)
+		for _, i := range order {
+			result[functions[i].InvocationStats.HashFunction] = sg.GenerateInvocationData(functions[i], common.Exponential, true, common.MinuteGranularity)
+		}
+
+		return result
+	}
+
+	stream := func(order []int) map[string]common.IATArray {
+		sg := NewSpecificationGenerator(42)
+
+		result := make(map[string]common.IATArray)
+		for _, i := range order {
+			s := sg.NewInvocationStream(functions[i], common.Exponential, true, common.MinuteGranularity)
+			for iat, _, ok := s.Next(); ok; iat, _, ok = s.Next() {
+				result[functions[i].InvocationStats.HashFunction] = append(result[functions[i].InvocationStats.HashFunction], iat...)
+			}
+		}
+
+		return result
+	}
+
+	// f2 is removed and the other functions are reordered
+	all, reduced := generate([]int{0, 1, 2}), generate([]int{2, 0})
+	allStreams, reducedStreams := stream([]int{0, 1, 2}), stream([]int{2, 0})
+	for _, hash := range []string{"f1", "f3"} {
+		if !reflect.DeepEqual(all[hash], reduced[hash]) {
+			t.Errorf("Specification of %s depends on the other functions.", hash)
+		}
+		if !reflect.DeepEqual(allStreams[hash], reducedStreams[hash]) {
+			t.Errorf("Invocation stream of %s depends on the other functions.", hash)
+		}
+	}
+
+	// functions with the same invocations still differ by their hash
+	if reflect.DeepEqual(all["f1"].IAT, all["f3"].IAT) {
+		t.Error("Functions with different hashes got the same IATs.")
+	}
+
+	sg := NewSpecificationGenerator(43)
+	if reflect.DeepEqual(all["f1"], sg.GenerateInvocationData(functions[0], common.Exponential, true, common.MinuteGranularity)) {
+		t.Error("Specification does not depend on the global seed.")
+	}
+}
@@ -0,0 +1,304 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"container/list"
+	"reflect"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+// WorkflowStage is a stage of a workflow, which invokes a function of the trace
+type WorkflowStage struct {
+	Name     string
+	Function *common.Function
+	// overrides of the runtime and memory sampled from the trace, zero if not overridden
+	RuntimeMs int
+	MemoryMiB int
+	// stages invoked once this one has completed
+	Next []*WorkflowStage
+	// stages that have to complete before this one is invoked
+	Previous []*WorkflowStage
+	// size of the data passed to the stage by each of its predecessors
+	Payload *common.PayloadSize
+
+	Specification *common.FunctionSpecification
+}
+
+// Workflow is a DAG of stages defined in the workflow file, invoked as a DAG starting from its entry stage
+type Workflow struct {
+	Name        string
+	Entry       *WorkflowStage
+	Stages      []*WorkflowStage
+	Invocations []int
+}
+
+// NewWorkflows resolves the stages of the workflow definitions onto the functions of the trace and validates the
+// structure of the workflows
+func NewWorkflows(definitions []config.WorkflowConfiguration, functions []*common.Function) []*Workflow {
+	functionsByName := make(map[string]*common.Function)
+	for _, function := range functions {
+		if function.InvocationStats != nil && function.InvocationStats.HashFunction != "" {
+			functionsByName[function.InvocationStats.HashFunction] = function
+		}
+		functionsByName[function.Name] = function
+	}
+
+	var result []*Workflow
+	for _, definition := range definitions {
+		workflow := &Workflow{Name: definition.Name, Invocations: definition.Invocations}
+		if workflow.Name == "" {
+			log.Fatal("Workflows need a name.")
+		}
+
+		stages := make(map[string]*WorkflowStage)
+		for _, stageDefinition := range definition.Stages {
+			function, ok := functionsByName[stageDefinition.Function]
+			if !ok {
+				log.Fatalf("Function %s of stage %s of workflow %s is not in the trace.", stageDefinition.Function, stageDefinition.Name, workflow.Name)
+			}
+			if _, ok = stages[stageDefinition.Name]; ok || stageDefinition.Name == "" {
+				log.Fatalf("Stages of workflow %s need unique names.", workflow.Name)
+			}
+
+			stage := &WorkflowStage{
+				Name:      stageDefinition.Name,
+				Function:  function,
+				RuntimeMs: stageDefinition.RuntimeMs,
+				MemoryMiB: stageDefinition.MemoryMiB,
+			}
+			stages[stage.Name] = stage
+			workflow.Stages = append(workflow.Stages, stage)
+		}
+
+		for _, edge := range definition.Edges {
+			from, ok := stages[edge.From]
+			if !ok {
+				log.Fatalf("Unknown stage %s in the edges of workflow %s.", edge.From, workflow.Name)
+			}
+
+			for _, name := range edge.To {
+				to, ok := stages[name]
+				if !ok {
+					log.Fatalf("Unknown stage %s in the edges of workflow %s.", name, workflow.Name)
+				}
+				for _, previous := range to.Previous {
+					if previous == from {
+						log.Fatalf("Duplicate edge from %s to %s in workflow %s.", edge.From, name, workflow.Name)
+					}
+				}
+
+				payload := NewPayloadSize(edge.Payload)
+				if len(to.Previous) > 0 && !reflect.DeepEqual(to.Payload, payload) {
+					log.Fatalf("Edges to stage %s of workflow %s need the same payload size.", name, workflow.Name)
+				}
+				to.Payload = payload
+
+				to.Previous = append(to.Previous, from)
+				from.Next = append(from.Next, to)
+			}
+		}
+
+		for _, stage := range workflow.Stages {
+			if len(stage.Previous) == 0 && (definition.Entry == "" || definition.Entry == stage.Name) {
+				if workflow.Entry != nil {
+					log.Fatalf("Workflow %s has multiple entry stages.", workflow.Name)
+				}
+				workflow.Entry = stage
+			}
+		}
+		if workflow.Entry == nil {
+			log.Fatalf("Workflow %s has no entry stage without predecessors.", workflow.Name)
+		}
+
+		reachable := make(map[*WorkflowStage]bool)
+		markReachableStages(workflow.Entry, reachable)
+		if len(reachable) != len(workflow.Stages) {
+			log.Fatalf("Only %d out of %d stages of workflow %s are reachable from its entry.", len(reachable), len(workflow.Stages), workflow.Name)
+		}
+
+		if hasCycle(workflow.Entry, make(map[*WorkflowStage]bool), make(map[*WorkflowStage]bool)) {
+			log.Fatalf("Workflow %s has a cycle.", workflow.Name)
+		}
+
+		result = append(result, workflow)
+	}
+
+	return result
+}
+
+func markReachableStages(stage *WorkflowStage, reachable map[*WorkflowStage]bool) {
+	if reachable[stage] {
+		return
+	}
+
+	reachable[stage] = true
+	for _, next := range stage.Next {
+		markReachableStages(next, reachable)
+	}
+}
+
+// hasCycle returns whether a cycle is reachable from the stage, where path holds the stages visited on the way to the
+// stage and done the ones already known not to reach a cycle
+func hasCycle(stage *WorkflowStage, path map[*WorkflowStage]bool, done map[*WorkflowStage]bool) bool {
+	if path[stage] {
+		return true
+	}
+	if done[stage] {
+		return false
+	}
+
+	path[stage] = true
+	for _, next := range stage.Next {
+		if hasCycle(next, path, done) {
+			return true
+		}
+	}
+	delete(path, stage)
+	done[stage] = true
+
+	return false
+}
+
+// stageDepth returns the length of the longest path from the entry of the workflow to the stage
+func stageDepth(stage *WorkflowStage, depths map[*WorkflowStage]int) int {
+	if depth, ok := depths[stage]; ok {
+		return depth
+	}
+
+	depth := 0
+	for _, previous := range stage.Previous {
+		depth = max(depth, stageDepth(previous, depths)+1)
+	}
+	depths[stage] = depth
+
+	return depth
+}
+
+// GenerateWorkflowData generates the IATs of the workflow and the runtime specifications of each of its stages, so
+// that the i-th invocation of the workflow uses the i-th runtime specification of each stage
+func (s *SpecificationGenerator) GenerateWorkflowData(workflow *Workflow, iatDistribution common.IatDistribution, shiftIAT bool, granularity common.TraceGranularity) {
+	entryStats := *workflow.Entry.Function.InvocationStats
+	if workflow.Invocations != nil {
+		// the invocations of the workflow cover the same time units as the trace
+		invocations := make([]int, len(entryStats.Invocations))
+		copy(invocations, workflow.Invocations)
+		entryStats.Invocations = invocations
+	}
+
+	entry := *workflow.Entry.Function
+	entry.Name = workflow.Name
+	entry.InvocationStats = &entryStats
+
+	// workflows with the same entry function still get their own invocations
+	spec := s.generateInvocationData(&entry, workflow.Name+"/"+functionIdentity(workflow.Entry.Function), iatDistribution, shiftIAT, granularity)
+
+	for _, stage := range workflow.Stages {
+		stage.Specification = &common.FunctionSpecification{PerMinuteCount: spec.PerMinuteCount}
+
+		if stage == workflow.Entry {
+			stage.Specification = spec
+		} else {
+			for i := 0; i < len(spec.IAT); i++ {
+				stage.Specification.RuntimeSpecification = append(stage.Specification.RuntimeSpecification, s.generateExecutionSpecs(stage.Function))
+			}
+		}
+
+		for i := range stage.Specification.RuntimeSpecification {
+			if stage.RuntimeMs > 0 {
+				stage.Specification.RuntimeSpecification[i].Runtime = stage.RuntimeMs
+			}
+			if stage.MemoryMiB > 0 {
+				stage.Specification.RuntimeSpecification[i].Memory = stage.MemoryMiB
+			}
+		}
+	}
+}
+
+// CreateWorkflowDAG creates the DAG of the workflow to be executed by the driver, where each stage gets a copy of its
+// function with the specification of the stage. It has to be called after the functions have been deployed, so that
+// the copies have their endpoints.
+func CreateWorkflowDAG(workflow *Workflow) *list.List {
+	dag := list.New()
+	appendWorkflowStage(dag, workflow.Entry, &workflowDAG{
+		identifier: workflow.Name + ",",
+		depths:     make(map[*WorkflowStage]int),
+		joins:      make(map[*WorkflowStage]*list.List),
+	})
+
+	return dag
+}
+
+// workflowDAG holds the state of the creation of the DAG of a workflow
+type workflowDAG struct {
+	identifier string
+	depths     map[*WorkflowStage]int
+	// branches starting with stages that have several predecessors, which are shared by all of them
+	joins map[*WorkflowStage]*list.List
+}
+
+// appendWorkflowStage appends the stage to the branch, continues the branch with its first successor with a single
+// predecessor and creates new branches for the other ones. Successors with several predecessors are joins, whose
+// branch is created once and added to the branches of each predecessor.
+func appendWorkflowStage(branch *list.List, stage *WorkflowStage, dag *workflowDAG) {
+	function := *stage.Function
+	function.Specification = stage.Specification
+
+	node := &common.Node{
+		Function:     &function,
+		Depth:        stageDepth(stage, dag.depths),
+		DAG:          dag.identifier,
+		Predecessors: len(stage.Previous),
+		Stage:        stage.Name,
+		Payload:      stage.Payload,
+	}
+	branch.PushBack(node)
+
+	var continuation *WorkflowStage
+	for _, next := range stage.Next {
+		if len(next.Previous) > 1 {
+			join, ok := dag.joins[next]
+			if !ok {
+				join = list.New()
+				dag.joins[next] = join
+				appendWorkflowStage(join, next, dag)
+			}
+			node.Branches = append(node.Branches, join)
+		} else if continuation == nil {
+			continuation = next
+		} else {
+			newBranch := list.New()
+			appendWorkflowStage(newBranch, next, dag)
+			node.Branches = append(node.Branches, newBranch)
+		}
+	}
+
+	if continuation != nil {
+		appendWorkflowStage(branch, continuation, dag)
+	}
+}
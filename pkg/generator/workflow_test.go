@@ -0,0 +1,179 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package generator
+
+import (
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+func createWorkflowFunctions(names ...string) []*common.Function {
+	var result []*common.Function
+	for _, name := range names {
+		function := testFunction
+		function.Name = name
+		function.InvocationStats = &common.FunctionInvocationStats{HashFunction: "hash-" + name, Invocations: []int{5, 5, 5}}
+
+		result = append(result, &function)
+	}
+
+	return result
+}
+
+var testWorkflow = config.WorkflowConfiguration{
+	Name:        "pipeline",
+	Invocations: []int{2, 0, 1, 7},
+	Stages: []config.WorkflowStageConfiguration{
+		{Name: "decode", Function: "decode"},
+		{Name: "detect", Function: "hash-detect", RuntimeMs: 250},
+		{Name: "thumbnail", Function: "thumbnail", MemoryMiB: 512},
+		{Name: "encode", Function: "encode"},
+	},
+	Edges: []config.WorkflowEdgeConfiguration{
+		{From: "decode", To: []string{"detect", "thumbnail"}},
+		{From: "detect", To: []string{"encode"}},
+	},
+}
+
+func TestWorkflowDAG(t *testing.T) {
+	workflows := NewWorkflows([]config.WorkflowConfiguration{testWorkflow}, createWorkflowFunctions("decode", "detect", "thumbnail", "encode"))
+	if len(workflows) != 1 || workflows[0].Entry.Name != "decode" {
+		t.Fatalf("Expected a workflow starting with decode, got %+v.", workflows)
+	}
+
+	sg := NewSpecificationGenerator(42)
+	sg.GenerateWorkflowData(workflows[0], common.Equidistant, false, common.MinuteGranularity)
+
+	dag := CreateWorkflowDAG(workflows[0])
+
+	// decode -> detect -> encode, with thumbnail branching off decode
+	var names []string
+	for element := dag.Front(); element != nil; element = element.Next() {
+		node := element.Value.(*common.Node)
+		names = append(names, node.Function.Name)
+
+		if node.DAG != "pipeline," {
+			t.Errorf("Unexpected DAG identifier %s.", node.DAG)
+		}
+		if len(node.Function.Specification.RuntimeSpecification) != 3 {
+			t.Errorf("Expected a runtime specification for each of the 3 invocations, got %d.", len(node.Function.Specification.RuntimeSpecification))
+		}
+	}
+	if len(names) != 3 || names[0] != "decode" || names[1] != "detect" || names[2] != "encode" {
+		t.Fatalf("Unexpected main branch %v.", names)
+	}
+
+	root := dag.Front().Value.(*common.Node)
+	if len(root.Branches) != 1 || root.Branches[0].Len() != 1 {
+		t.Fatalf("Expected a single branch with thumbnail, got %v.", root.Branches)
+	}
+	thumbnail := root.Branches[0].Front().Value.(*common.Node)
+	if thumbnail.Function.Name != "thumbnail" || thumbnail.Depth != 1 {
+		t.Errorf("Unexpected branch %+v.", thumbnail)
+	}
+
+	// the invocations of the workflow are cropped to the trace
+	spec := root.Function.Specification
+	if len(spec.IAT) != 3 || spec.PerMinuteCount[0] != 2 || spec.PerMinuteCount[1] != 0 || spec.PerMinuteCount[2] != 1 {
+		t.Errorf("Unexpected specification of the workflow %+v.", spec)
+	}
+
+	for _, runtime := range dag.Front().Next().Value.(*common.Node).Function.Specification.RuntimeSpecification {
+		if runtime.Runtime != 250 {
+			t.Errorf("Runtime of detect should be overridden, got %d.", runtime.Runtime)
+		}
+	}
+	for _, runtime := range thumbnail.Function.Specification.RuntimeSpecification {
+		if runtime.Memory != 512 {
+			t.Errorf("Memory of thumbnail should be overridden, got %d.", runtime.Memory)
+		}
+	}
+
+	// the functions of the trace are left untouched
+	if workflows[0].Entry.Function.Specification != nil || workflows[0].Entry.Function.InvocationStats.Invocations[0] != 5 {
+		t.Error("Functions of the trace should not be modified by workflows.")
+	}
+}
+
+func TestWorkflowEntryInvocations(t *testing.T) {
+	definition := testWorkflow
+	definition.Invocations = nil
+
+	workflows := NewWorkflows([]config.WorkflowConfiguration{definition}, createWorkflowFunctions("decode", "detect", "thumbnail", "encode"))
+	NewSpecificationGenerator(42).GenerateWorkflowData(workflows[0], common.Equidistant, false, common.MinuteGranularity)
+
+	if count := len(CreateWorkflowDAG(workflows[0]).Front().Value.(*common.Node).Function.Specification.IAT); count != 15 {
+		t.Errorf("Workflow should follow the invocations of its entry function, got %d invocations.", count)
+	}
+}
+
+func TestWorkflowJoin(t *testing.T) {
+	// map-reduce: split fans out to two mappers that are joined by reduce, followed by store
+	definition := config.WorkflowConfiguration{
+		Name: "map-reduce",
+		Stages: []config.WorkflowStageConfiguration{
+			{Name: "split", Function: "split"},
+			{Name: "map-1", Function: "map-1"},
+			{Name: "map-2", Function: "map-2"},
+			{Name: "reduce", Function: "reduce"},
+			{Name: "store", Function: "store"},
+		},
+		Edges: []config.WorkflowEdgeConfiguration{
+			{From: "split", To: []string{"map-1", "map-2"}},
+			{From: "map-1", To: []string{"reduce"}, Payload: &config.PayloadSizeConfiguration{MeanBytes: 512}},
+			{From: "map-2", To: []string{"reduce"}, Payload: &config.PayloadSizeConfiguration{MeanBytes: 512}},
+			{From: "reduce", To: []string{"store"}},
+		},
+	}
+
+	workflows := NewWorkflows([]config.WorkflowConfiguration{definition}, createWorkflowFunctions("split", "map-1", "map-2", "reduce", "store"))
+	dag := CreateWorkflowDAG(workflows[0])
+
+	if dag.Len() != 2 || dag.Back().Value.(*common.Node).Function.Name != "map-1" {
+		t.Fatalf("Expected split -> map-1 as main branch, got %d nodes.", dag.Len())
+	}
+	mapper := dag.Front().Value.(*common.Node).Branches[0].Front().Value.(*common.Node)
+	if mapper.Function.Name != "map-2" {
+		t.Fatalf("Expected map-2 to branch off split, got %s.", mapper.Function.Name)
+	}
+
+	joinBranch := dag.Back().Value.(*common.Node).Branches[0]
+	if len(mapper.Branches) != 1 || mapper.Branches[0] != joinBranch {
+		t.Fatal("Both mappers should share the branch of the join.")
+	}
+
+	join := joinBranch.Front().Value.(*common.Node)
+	if join.Function.Name != "reduce" || join.Predecessors != 2 || join.Depth != 2 {
+		t.Errorf("Unexpected join %+v.", join)
+	}
+	if join.Payload == nil || join.Payload.MeanBytes != 512 || mapper.Payload != nil {
+		t.Error("Only the edges to the join should pass data.")
+	}
+	if joinBranch.Len() != 2 || joinBranch.Back().Value.(*common.Node).Predecessors != 1 {
+		t.Error("The join should be followed by store in its branch.")
+	}
+}
@@ -55,6 +55,8 @@ type ExecutionRecordBase struct {
 
 	ConnectionTimeout bool `csv:"connectionTimeout"`
 	FunctionTimeout   bool `csv:"functionTimeout"`
+	// Throttled is set when the platform rejected the invocation for lack of capacity
+	Throttled bool `csv:"throttled"`
 }
 
 type ExecutionRecordOpenWhisk struct {
@@ -76,11 +78,52 @@ type ExecutionRecord struct {
 	ActualMemoryUsage       uint32 `csv:"actualMemoryUsage"`
 	MemoryAllocationTimeout bool   `csv:"memoryAllocationTimeout"`
 
-	AsyncResponseID     string `csv:"-"`
+	AsyncResponseID string `csv:"-"`
+	// Tenant of the invoked function in multi-tenant experiments, kept for records resolved after the experiment
+	Tenant              string `csv:"-"`
 	TimeToSubmitMs      int64  `csv:"timeToSubmitMs"`
 	UserCodeExecutionMs int64  `csv:"userCodeExecutionMs"`
 
 	TimeToGetResponseMs int64 `csv:"timeToGetResponseMs"`
+
+	// Stage of the DAG and the stage whose completion started it, empty outside of DAG mode
+	Stage       string `csv:"stage"`
+	ParentStage string `csv:"parentStage"`
+	// Size of the data passed by the predecessors of the stage and the time in microseconds it took to send it
+	PayloadBytes int   `csv:"payloadBytes"`
+	TransferTime int64 `csv:"transferTime"`
+}
+
+type TenantRecord struct {
+	Tenant    string `csv:"tenant"`
+	Functions int    `csv:"functions"`
+
+	Invocations int `csv:"invocations"`
+	Failed      int `csv:"failed"`
+	Throttled   int `csv:"throttled"`
+	ColdStarts  int `csv:"coldStarts"`
+
+	// Latencies of successful invocations in milliseconds
+	AverageLatency float64 `csv:"averageLatency"`
+	P50Latency     float64 `csv:"p50Latency"`
+	P99Latency     float64 `csv:"p99Latency"`
+}
+
+type WorkflowRecord struct {
+	Phase        int    `csv:"phase"`
+	DAG          string `csv:"dag"`
+	InvocationID string `csv:"invocationID"`
+	StartTime    int64  `csv:"startTime"`
+
+	// Measurements in microseconds
+	Latency int64 `csv:"latency"`
+
+	NodesExecuted int `csv:"nodesExecuted"`
+	FailedNodes   int `csv:"failedNodes"`
+
+	// Sum of the response times of the stages on the critical path in microseconds
+	CriticalPathLength int64  `csv:"criticalPathLength"`
+	CriticalPath       string `csv:"criticalPath"`
 }
 
 type DeploymentScale struct {
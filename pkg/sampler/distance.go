@@ -0,0 +1,121 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package sampler
+
+import (
+	"math"
+	"sort"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+type Distance struct {
+	Feature Feature
+	// Wasserstein-1 distance between the feature distributions of the sample and the original trace
+	Wasserstein float64
+	// Wasserstein distance divided by the mean of the feature in the original trace
+	Normalized float64
+}
+
+// ComputeDistances compares the distribution of each feature in the sample with the one in the original trace
+func ComputeDistances(original []*common.Function, sample []*common.Function) []Distance {
+	var result []Distance
+
+	for _, feature := range Features {
+		originalValues := featureValues(original, feature)
+		sampleValues := featureValues(sample, feature)
+
+		distance := Distance{
+			Feature:     feature,
+			Wasserstein: WassersteinDistance(originalValues, sampleValues),
+		}
+
+		if mean := average(originalValues); mean > 0 {
+			distance.Normalized = distance.Wasserstein / mean
+		}
+
+		result = append(result, distance)
+	}
+
+	return result
+}
+
+// WassersteinDistance computes the first Wasserstein distance between two empirical distributions as the area
+// between their cumulative distribution functions
+func WassersteinDistance(a []float64, b []float64) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return math.NaN()
+	}
+
+	a, b = sortedCopy(a), sortedCopy(b)
+	all := sortedCopy(append(append([]float64{}, a...), b...))
+
+	distance := 0.0
+	i, j := 0, 0
+	for k := 0; k < len(all)-1; k++ {
+		for i < len(a) && a[i] <= all[k] {
+			i++
+		}
+		for j < len(b) && b[j] <= all[k] {
+			j++
+		}
+
+		cdfA := float64(i) / float64(len(a))
+		cdfB := float64(j) / float64(len(b))
+
+		distance += math.Abs(cdfA-cdfB) * (all[k+1] - all[k])
+	}
+
+	return distance
+}
+
+func featureValues(functions []*common.Function, feature Feature) []float64 {
+	values := make([]float64, len(functions))
+	for i, function := range functions {
+		values[i] = feature.Value(function)
+	}
+
+	return values
+}
+
+func sortedCopy(values []float64) []float64 {
+	result := append([]float64{}, values...)
+	sort.Float64s(result)
+
+	return result
+}
+
+func average(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+	}
+
+	return sum / float64(len(values))
+}
@@ -0,0 +1,62 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package sampler
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWassersteinDistance(t *testing.T) {
+	tests := []struct {
+		testName string
+		a        []float64
+		b        []float64
+		expected float64
+	}{
+		{testName: "identical", a: []float64{1, 2, 3}, b: []float64{3, 1, 2}, expected: 0},
+		{testName: "shifted", a: []float64{1, 2, 3}, b: []float64{2, 3, 4}, expected: 1},
+		{testName: "single_points", a: []float64{0}, b: []float64{5}, expected: 5},
+		{testName: "different_sizes", a: []float64{0, 1}, b: []float64{0, 0, 1, 1}, expected: 0},
+		{testName: "subset", a: []float64{0, 10}, b: []float64{0}, expected: 5},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			distance := WassersteinDistance(test.a, test.b)
+			if math.Abs(distance-test.expected) > 1e-9 {
+				t.Errorf("Expected distance %f, got %f.", test.expected, distance)
+			}
+
+			if reverse := WassersteinDistance(test.b, test.a); math.Abs(distance-reverse) > 1e-9 {
+				t.Error("Wasserstein distance is not symmetric.")
+			}
+		})
+	}
+
+	if !math.IsNaN(WassersteinDistance(nil, []float64{1})) {
+		t.Error("Distance to an empty distribution should be undefined.")
+	}
+}
@@ -0,0 +1,226 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package sampler
+
+import (
+	"math/rand"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+// Feature is a per-function characteristic of the trace used for stratification and for comparing a sample with the
+// original trace
+type Feature int
+
+const (
+	InvocationRate Feature = iota // average number of invocations per minute
+	Runtime                       // average runtime in ms
+	Memory                        // average allocated memory in MiB
+)
+
+var Features = []Feature{InvocationRate, Runtime, Memory}
+
+func (f Feature) String() string {
+	switch f {
+	case InvocationRate:
+		return "InvocationRate"
+	case Runtime:
+		return "Runtime"
+	case Memory:
+		return "Memory"
+	default:
+		return "Unknown"
+	}
+}
+
+// Value returns the feature of the function, where missing statistics are treated as zero
+func (f Feature) Value(function *common.Function) float64 {
+	switch f {
+	case InvocationRate:
+		if function.InvocationStats == nil || len(function.InvocationStats.Invocations) == 0 {
+			return 0
+		}
+
+		total := 0
+		for _, count := range function.InvocationStats.Invocations {
+			total += count
+		}
+
+		return float64(total) / float64(len(function.InvocationStats.Invocations))
+	case Runtime:
+		if function.RuntimeStats == nil {
+			return 0
+		}
+
+		return function.RuntimeStats.Average
+	case Memory:
+		if function.MemoryStats == nil {
+			return 0
+		}
+
+		return function.MemoryStats.Average
+	default:
+		log.Fatalf("Unsupported sampling feature %d.", f)
+	}
+
+	return 0
+}
+
+// StratifiedSample selects sampleSize functions from the trace. Functions are split into quantile bins by their
+// invocation rate, each bin is then split by runtime and finally by memory. The sample is allocated to the bins
+// proportionally to their size at each level, so that the sample preserves the joint distribution of the features,
+// giving priority to invocation rate. The selection is reproducible for the same seed and the selected functions keep
+// their order in the trace.
+func StratifiedSample(functions []*common.Function, sampleSize int, binsPerFeature int, seed int64) []*common.Function {
+	if sampleSize >= len(functions) {
+		log.Warnf("Sample size %d is not smaller than the trace with %d functions. Keeping all the functions.", sampleSize, len(functions))
+		return append([]*common.Function{}, functions...)
+	}
+	if sampleSize <= 0 {
+		return nil
+	}
+
+	binsPerFeature = common.MaxOf(binsPerFeature, 1)
+
+	s := &stratifier{
+		bins:           make([][]int, len(Features)),
+		binsPerFeature: binsPerFeature,
+		gen:            rand.New(rand.NewSource(seed)),
+	}
+	for i, feature := range Features {
+		s.bins[i] = quantileBins(functions, feature, binsPerFeature)
+	}
+
+	all := make([]int, len(functions))
+	for i := range all {
+		all[i] = i
+	}
+
+	selected := s.sample(all, 0, sampleSize)
+	sort.Ints(selected)
+
+	result := make([]*common.Function, len(selected))
+	for i, index := range selected {
+		result[i] = functions[index]
+	}
+
+	return result
+}
+
+type stratifier struct {
+	// bin of each function per feature
+	bins           [][]int
+	binsPerFeature int
+	gen            *rand.Rand
+}
+
+// sample selects size functions out of members, which are in the same bin for all the features before level
+func (s *stratifier) sample(members []int, level int, size int) []int {
+	if size == 0 {
+		return nil
+	}
+
+	if level == len(s.bins) {
+		var result []int
+		for _, i := range s.gen.Perm(len(members))[:size] {
+			result = append(result, members[i])
+		}
+
+		return result
+	}
+
+	strata := make([][]int, s.binsPerFeature)
+	for _, member := range members {
+		bin := s.bins[level][member]
+		strata[bin] = append(strata[bin], member)
+	}
+
+	var result []int
+	for i, allocation := range allocateProportionally(strata, size, len(members)) {
+		result = append(result, s.sample(strata[i], level+1, allocation)...)
+	}
+
+	return result
+}
+
+// quantileBins assigns each function to one of the equally populated bins by the rank of its feature value, where
+// functions with the same value always end up in the same bin
+func quantileBins(functions []*common.Function, feature Feature, bins int) []int {
+	values := make([]float64, len(functions))
+	order := make([]int, len(functions))
+	for i, function := range functions {
+		values[i] = feature.Value(function)
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return values[order[i]] < values[order[j]]
+	})
+
+	result := make([]int, len(functions))
+	for rank, index := range order {
+		if rank > 0 && values[order[rank-1]] == values[index] {
+			result[index] = result[order[rank-1]]
+			continue
+		}
+
+		result[index] = rank * bins / len(functions)
+	}
+
+	return result
+}
+
+// allocateProportionally splits sampleSize among the strata proportionally to their size using the largest remainder
+// method
+func allocateProportionally(strata [][]int, sampleSize int, total int) []int {
+	allocation := make([]int, len(strata))
+	remainders := make([]float64, len(strata))
+
+	allocated := 0
+	for i, stratum := range strata {
+		quota := float64(sampleSize) * float64(len(stratum)) / float64(total)
+
+		allocation[i] = int(quota)
+		remainders[i] = quota - float64(allocation[i])
+		allocated += allocation[i]
+	}
+
+	byRemainder := make([]int, len(strata))
+	for i := range byRemainder {
+		byRemainder[i] = i
+	}
+	sort.SliceStable(byRemainder, func(i, j int) bool {
+		return remainders[byRemainder[i]] > remainders[byRemainder[j]]
+	})
+
+	for i := 0; allocated < sampleSize; i++ {
+		allocation[byRemainder[i]]++
+		allocated++
+	}
+
+	return allocation
+}
@@ -0,0 +1,182 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package sampler
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/trace"
+)
+
+func createTestFunctions(count int) []*common.Function {
+	var result []*common.Function
+
+	for i := 0; i < count; i++ {
+		hash := fmt.Sprintf("function%03d", i)
+
+		result = append(result, &common.Function{
+			Name: hash,
+			InvocationStats: &common.FunctionInvocationStats{
+				HashOwner:    "owner",
+				HashApp:      fmt.Sprintf("app%03d", i/2),
+				HashFunction: hash,
+				Trigger:      "http",
+				Invocations:  []int{i % 10, (i * 7) % 13, 1},
+			},
+			RuntimeStats: &common.FunctionRuntimeStats{
+				HashOwner:    "owner",
+				HashApp:      fmt.Sprintf("app%03d", i/2),
+				HashFunction: hash,
+				Average:      float64((i * 37) % 1000),
+				Count:        100,
+				Maximum:      1000,
+			},
+			MemoryStats: &common.FunctionMemoryStats{
+				HashOwner:    "owner",
+				HashApp:      fmt.Sprintf("app%03d", i/2),
+				HashFunction: hash,
+				Count:        100,
+				Average:      float64(128 + (i*53)%512),
+			},
+		})
+	}
+
+	return result
+}
+
+func TestStratifiedSample(t *testing.T) {
+	functions := createTestFunctions(200)
+
+	tests := []struct {
+		testName     string
+		sampleSize   int
+		expectedSize int
+	}{
+		{testName: "sample_empty", sampleSize: 0, expectedSize: 0},
+		{testName: "sample_single", sampleSize: 1, expectedSize: 1},
+		{testName: "sample_quarter", sampleSize: 50, expectedSize: 50},
+		{testName: "sample_odd", sampleSize: 77, expectedSize: 77},
+		{testName: "sample_everything", sampleSize: 500, expectedSize: 200},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			sample := StratifiedSample(functions, test.sampleSize, 3, 42)
+			if len(sample) != test.expectedSize {
+				t.Fatalf("Expected %d functions, got %d.", test.expectedSize, len(sample))
+			}
+
+			seen := make(map[string]bool)
+			previous := ""
+			for _, function := range sample {
+				if seen[function.Name] {
+					t.Errorf("Function %s has been selected twice.", function.Name)
+				}
+				if function.Name < previous {
+					t.Error("Sampled functions do not preserve the order of the trace.")
+				}
+
+				seen[function.Name] = true
+				previous = function.Name
+			}
+
+			if !reflect.DeepEqual(sample, StratifiedSample(functions, test.sampleSize, 3, 42)) {
+				t.Error("Sampling with the same seed is not reproducible.")
+			}
+		})
+	}
+}
+
+func TestStratifiedSampleProportions(t *testing.T) {
+	functions := createTestFunctions(100)
+	// a quarter of the functions are heavy hitters, which should make a quarter of the sample
+	for i := 0; i < 25; i++ {
+		functions[i*4].InvocationStats.Invocations = []int{1000, 1000, 1000}
+	}
+
+	sample := StratifiedSample(functions, 20, 4, 7)
+
+	heavyHitters := 0
+	for _, function := range sample {
+		if function.InvocationStats.Invocations[0] == 1000 {
+			heavyHitters++
+		}
+	}
+
+	if heavyHitters != 5 {
+		t.Errorf("Expected 5 heavy hitters in the sample, got %d.", heavyHitters)
+	}
+
+	for _, distance := range ComputeDistances(functions, functions) {
+		if distance.Wasserstein != 0 {
+			t.Errorf("Distance of %s between identical traces should be zero.", distance.Feature)
+		}
+	}
+}
+
+func TestWriteTrace(t *testing.T) {
+	functions := createTestFunctions(10)
+	for _, function := range functions {
+		function.DirigentMetadata = &common.DirigentMetadata{Image: "docker.io/test/" + function.Name, Port: 80}
+	}
+
+	outputPath := t.TempDir()
+	WriteTrace(functions[2:5], outputPath)
+
+	parsed := trace.NewAzureParser(outputPath, 3).Parse()
+	trace.NewDirigentJSONEnricher(outputPath, "Dirigent").Enrich(parsed)
+
+	if len(parsed) != 3 {
+		t.Fatalf("Expected 3 functions in the written trace, got %d.", len(parsed))
+	}
+
+	for i, function := range parsed {
+		original := functions[i+2]
+
+		if !reflect.DeepEqual(function.InvocationStats, original.InvocationStats) ||
+			!reflect.DeepEqual(function.RuntimeStats, original.RuntimeStats) ||
+			!reflect.DeepEqual(function.MemoryStats, original.MemoryStats) {
+
+			t.Errorf("Function %s has not been written correctly.", original.Name)
+		}
+
+		if function.DirigentMetadata == nil || function.DirigentMetadata.Image != original.DirigentMetadata.Image ||
+			function.DirigentMetadata.HashFunction != original.InvocationStats.HashFunction {
+
+			t.Errorf("Dirigent metadata of function %s has not been written correctly.", original.Name)
+		}
+	}
+
+	for _, file := range []string{"invocations.csv", "durations.csv", "memory.csv", "dirigent.json"} {
+		if _, err := os.Stat(filepath.Join(outputPath, file)); err != nil {
+			t.Errorf("File %s has not been written.", file)
+		}
+	}
+}
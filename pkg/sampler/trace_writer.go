@@ -0,0 +1,150 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package sampler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/gocarina/gocsv"
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/trace"
+)
+
+// AttachDirigentMetadata reads the Dirigent metadata of the functions from dirigent.json in the trace directory.
+// Functions without an entry get the metadata of the Knative service YAML, so that the sample can be run on Dirigent.
+func AttachDirigentMetadata(functions []*common.Function, tracePath string, yamlPath string) {
+	if _, err := os.Stat(filepath.Join(tracePath, "dirigent.json")); err == nil {
+		trace.NewDirigentJSONEnricher(tracePath, "Dirigent").Enrich(functions)
+	}
+
+	var missing []*common.Function
+	for _, function := range functions {
+		if function.DirigentMetadata == nil {
+			missing = append(missing, function)
+		}
+	}
+
+	if len(missing) > 0 && yamlPath != "" {
+		log.Infof("Using %s as Dirigent metadata for %d functions without an entry in dirigent.json.", yamlPath, len(missing))
+		trace.NewKnativeYAMLEnricher(yamlPath, "Knative").Enrich(missing)
+	}
+}
+
+// WriteTrace writes the invocation, duration and memory traces of the functions in the format of the Azure trace, as
+// well as their Dirigent metadata, into the output directory
+func WriteTrace(functions []*common.Function, outputPath string) {
+	if err := os.MkdirAll(outputPath, 0755); err != nil {
+		log.Fatalf("Failed to create output directory %s - %v", outputPath, err)
+	}
+
+	writeInvocationTrace(functions, filepath.Join(outputPath, "invocations.csv"))
+
+	var runtimeStats []*common.FunctionRuntimeStats
+	var memoryStats []*common.FunctionMemoryStats
+	var dirigentMetadata []*common.DirigentMetadata
+
+	for _, function := range functions {
+		if function.RuntimeStats != nil {
+			runtimeStats = append(runtimeStats, function.RuntimeStats)
+		}
+		if function.MemoryStats != nil {
+			memoryStats = append(memoryStats, function.MemoryStats)
+		}
+		if function.DirigentMetadata != nil {
+			metadata := *function.DirigentMetadata
+			metadata.HashFunction = function.InvocationStats.HashFunction
+
+			dirigentMetadata = append(dirigentMetadata, &metadata)
+		}
+	}
+
+	writeCSV(runtimeStats, filepath.Join(outputPath, "durations.csv"))
+	writeCSV(memoryStats, filepath.Join(outputPath, "memory.csv"))
+
+	if len(dirigentMetadata) > 0 {
+		data, err := json.MarshalIndent(dirigentMetadata, "", "  ")
+		common.Check(err)
+
+		if err = os.WriteFile(filepath.Join(outputPath, "dirigent.json"), data, 0644); err != nil {
+			log.Fatalf("Failed to write Dirigent metadata - %v", err)
+		}
+	}
+}
+
+func writeInvocationTrace(functions []*common.Function, path string) {
+	file, err := os.Create(path)
+	if err != nil {
+		log.Fatalf("Failed to create invocation trace %s - %v", path, err)
+	}
+	defer file.Close()
+
+	minutes := 0
+	for _, function := range functions {
+		minutes = common.MaxOf(minutes, len(function.InvocationStats.Invocations))
+	}
+
+	writer := csv.NewWriter(file)
+
+	header := []string{"HashOwner", "HashApp", "HashFunction", "Trigger"}
+	for minute := 1; minute <= minutes; minute++ {
+		header = append(header, strconv.Itoa(minute))
+	}
+	common.Check(writer.Write(header))
+
+	for _, function := range functions {
+		stats := function.InvocationStats
+
+		record := []string{stats.HashOwner, stats.HashApp, stats.HashFunction, stats.Trigger}
+		for minute := 0; minute < minutes; minute++ {
+			count := 0
+			if minute < len(stats.Invocations) {
+				count = stats.Invocations[minute]
+			}
+
+			record = append(record, strconv.Itoa(count))
+		}
+		common.Check(writer.Write(record))
+	}
+
+	writer.Flush()
+	common.Check(writer.Error())
+}
+
+func writeCSV(records interface{}, path string) {
+	file, err := os.Create(path)
+	if err != nil {
+		log.Fatalf("Failed to create %s - %v", path, err)
+	}
+	defer file.Close()
+
+	if err = gocsv.MarshalFile(records, file); err != nil {
+		log.Fatalf("Failed to write %s - %v", path, err)
+	}
+}
@@ -0,0 +1,213 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package trace
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/generator"
+)
+
+// AlibabaTraceParser parses request logs of Alibaba Function Compute converted into requests.csv, where each row is a
+// single request with its timestamp (ms), function, execution time (ms) and memory (MiB), as described in
+// docs/configuration.md. Requests are aggregated into per-minute or per-second counts depending on the trace
+// granularity, while runtime and memory statistics are computed over all requests in the trace.
+type AlibabaTraceParser struct {
+	DirectoryPath string
+
+	duration              int
+	granularity           common.TraceGranularity
+	functionNameGenerator *rand.Rand
+}
+
+type alibabaFunction struct {
+	hashOwner    string
+	hashApp      string
+	hashFunction string
+
+	timestamps []float64
+	runtime    []float64
+	memory     []float64
+}
+
+func NewAlibabaParser(directoryPath string, totalDuration int, granularity common.TraceGranularity) *AlibabaTraceParser {
+	return &AlibabaTraceParser{
+		DirectoryPath: directoryPath,
+
+		duration:              totalDuration,
+		granularity:           granularity,
+		functionNameGenerator: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+func (p *AlibabaTraceParser) Parse() []*common.Function {
+	traceFunctions, traceStart := parseRequestTrace(p.DirectoryPath + "/requests.csv")
+
+	unit := 60_000.0 // ms
+	if p.granularity == common.SecondGranularity {
+		unit = 1_000.0
+	}
+	duration := common.MaxOf(p.duration, 1)
+
+	var result []*common.Function
+
+	gen := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	for i, traceFunction := range traceFunctions {
+		invocations := make([]int, duration)
+		for _, timestamp := range traceFunction.timestamps {
+			index := int(math.Floor((timestamp - traceStart) / unit))
+			if index < duration {
+				invocations[index]++
+			}
+		}
+
+		runtimeStats := createRuntimeStats(traceFunction.runtime)
+		runtimeStats.HashOwner, runtimeStats.HashApp, runtimeStats.HashFunction = traceFunction.hashOwner, traceFunction.hashApp, traceFunction.hashFunction
+
+		memoryStats := createMemoryStats(traceFunction.memory)
+		memoryStats.HashOwner, memoryStats.HashApp, memoryStats.HashFunction = traceFunction.hashOwner, traceFunction.hashApp, traceFunction.hashFunction
+
+		function := &common.Function{
+			Name: fmt.Sprintf("%s-%d-%d", common.FunctionNamePrefix, i, p.functionNameGenerator.Uint64()),
+
+			InvocationStats: &common.FunctionInvocationStats{
+				HashOwner:    traceFunction.hashOwner,
+				HashApp:      traceFunction.hashApp,
+				HashFunction: traceFunction.hashFunction,
+				Invocations:  invocations,
+			},
+			RuntimeStats: runtimeStats,
+			MemoryStats:  memoryStats,
+
+			ColdStartBusyLoopMs: generator.ComputeBusyLoopPeriod(generator.GenerateMemorySpec(gen, gen.Float64(), memoryStats)),
+		}
+
+		result = append(result, function)
+	}
+
+	return result
+}
+
+// parseRequestTrace groups requests by function in the order of their first appearance and returns the timestamp of
+// the earliest request, which is considered to be the beginning of the trace
+func parseRequestTrace(traceFile string) ([]*alibabaFunction, float64) {
+	log.Infof("Parsing Alibaba request trace: %s", traceFile)
+
+	csvfile, err := os.Open(traceFile)
+	if err != nil {
+		log.Fatal("Failed to open Alibaba request trace file.", err)
+	}
+	defer csvfile.Close()
+
+	reader := csv.NewReader(csvfile)
+
+	var result []*alibabaFunction
+	functionIndex := make(map[string]int)
+	traceStart := math.MaxFloat64
+
+	timestampIndex, userIndex, appIndex, functionColumnIndex, durationIndex, memoryIndex := -1, -1, -1, -1, -1, -1
+
+	for rowID := 0; ; rowID++ {
+		record, err := reader.Read()
+
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			log.Fatal(err)
+		}
+
+		if rowID == 0 {
+			// Parse header
+			for i := 0; i < len(record); i++ {
+				switch strings.ToLower(strings.TrimSpace(record[i])) {
+				case "timestamp":
+					timestampIndex = i
+				case "userid":
+					userIndex = i
+				case "appid":
+					appIndex = i
+				case "functionid":
+					functionColumnIndex = i
+				case "duration":
+					durationIndex = i
+				case "memory":
+					memoryIndex = i
+				}
+			}
+
+			if timestampIndex == -1 || functionColumnIndex == -1 || durationIndex == -1 || memoryIndex == -1 {
+				log.Fatal("Alibaba request trace does not contain at least one of the columns timestamp, functionid, duration, memory.")
+			}
+
+			continue
+		}
+
+		timestamp, err := strconv.ParseFloat(record[timestampIndex], 64)
+		common.Check(err)
+		duration, err := strconv.ParseFloat(record[durationIndex], 64)
+		common.Check(err)
+		memory, err := strconv.ParseFloat(record[memoryIndex], 64)
+		common.Check(err)
+
+		index, ok := functionIndex[record[functionColumnIndex]]
+		if !ok {
+			index = len(result)
+			functionIndex[record[functionColumnIndex]] = index
+
+			function := &alibabaFunction{hashFunction: record[functionColumnIndex]}
+			if userIndex != -1 {
+				function.hashOwner = record[userIndex]
+			}
+			if appIndex != -1 {
+				function.hashApp = record[appIndex]
+			} else {
+				function.hashApp = function.hashFunction
+			}
+
+			result = append(result, function)
+		}
+
+		function := result[index]
+		function.timestamps = append(function.timestamps, timestamp)
+		function.runtime = append(function.runtime, duration)
+		function.memory = append(function.memory, memory)
+
+		traceStart = math.Min(traceStart, timestamp)
+	}
+
+	return result, traceStart
+}
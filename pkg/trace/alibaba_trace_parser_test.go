@@ -0,0 +1,75 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package trace
+
+import (
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+func TestAlibabaTraceParser(t *testing.T) {
+	functions := NewAlibabaParser("test_data/alibaba", 2, common.MinuteGranularity).Parse()
+
+	if len(functions) != 2 {
+		t.Fatalf("Expected 2 functions, got %d.", len(functions))
+	}
+
+	f1, f2 := functions[0], functions[1]
+	if f1.InvocationStats.HashOwner != "u1" || f1.InvocationStats.HashApp != "a1" || f1.InvocationStats.HashFunction != "f1" ||
+		f2.InvocationStats.HashOwner != "u2" || f2.MemoryStats.HashFunction != "f2" {
+
+		t.Error("Unexpected function identifiers.")
+	}
+
+	if len(f1.InvocationStats.Invocations) != 2 || f1.InvocationStats.Invocations[0] != 2 || f1.InvocationStats.Invocations[1] != 1 ||
+		len(f2.InvocationStats.Invocations) != 2 || f2.InvocationStats.Invocations[0] != 1 || f2.InvocationStats.Invocations[1] != 0 {
+
+		t.Error("Unexpected per-minute invocations.")
+	}
+
+	if !floatEqual(f1.RuntimeStats.Average, 200) || !floatEqual(f1.RuntimeStats.Count, 3) ||
+		!floatEqual(f1.RuntimeStats.Percentile100, 300) || !floatEqual(f2.RuntimeStats.Minimum, 10) {
+
+		t.Error("Unexpected runtime statistics.")
+	}
+
+	if !floatEqual(f1.MemoryStats.Percentile50, 128) || !floatEqual(f1.MemoryStats.Percentile100, 256) ||
+		!floatEqual(f2.MemoryStats.Average, 384) {
+
+		t.Error("Unexpected memory statistics.")
+	}
+
+	functions = NewAlibabaParser("test_data/alibaba", 10, common.SecondGranularity).Parse()
+
+	expected := [][]int{{1, 0, 0, 0, 0, 0, 0, 0, 0, 0}, {0, 0, 0, 0, 0, 1, 0, 0, 0, 0}}
+	for i, function := range functions {
+		for j := 0; j < len(expected[i]); j++ {
+			if function.InvocationStats.Invocations[j] != expected[i][j] {
+				t.Errorf("Unexpected number of invocations of function %d in second %d.", i, j)
+			}
+		}
+	}
+}
@@ -0,0 +1,282 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package trace
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/generator"
+)
+
+const (
+	// Azure2021TraceFile is the name of the per-invocation trace released with the Azure Functions 2021 dataset
+	// https://github.com/Azure/AzurePublicDataset/blob/master/AzureFunctionsInvocationTrace2021.md
+	Azure2021TraceFile = "AzureFunctionsInvocationTraceForTwoWeeksJan2021.txt"
+
+	// azure2021DefaultMemoryMiB is used when no memory.csv is provided, as the 2021 dataset does not contain memory
+	azure2021DefaultMemoryMiB = 128
+)
+
+// Azure2021TraceParser parses the Azure Functions 2021 trace, where each row is a single invocation with its end
+// timestamp and duration. As the arrival time of each invocation is known, function specifications are created
+// directly from the trace instead of being sampled from per-minute invocation counts.
+type Azure2021TraceParser struct {
+	DirectoryPath string
+
+	duration              int
+	functionNameGenerator *rand.Rand
+}
+
+type azure2021Invocation struct {
+	arrival  float64 // μs since the beginning of the trace
+	duration float64 // ms
+}
+
+type azure2021Function struct {
+	hashApp      string
+	hashFunction string
+
+	invocations []azure2021Invocation
+}
+
+func NewAzure2021Parser(directoryPath string, totalDuration int) *Azure2021TraceParser {
+	return &Azure2021TraceParser{
+		DirectoryPath: directoryPath,
+
+		duration:              totalDuration,
+		functionNameGenerator: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+func (p *Azure2021TraceParser) Parse() []*common.Function {
+	invocationPath := p.DirectoryPath + "/" + Azure2021TraceFile
+	memoryPath := p.DirectoryPath + "/memory.csv"
+
+	traceFunctions := parsePerInvocationTrace(invocationPath, p.duration)
+
+	memoryByHashFunction := make(map[string]*common.FunctionMemoryStats)
+	if _, err := os.Stat(memoryPath); err == nil {
+		memoryByHashFunction = createMemoryMap(parseMemoryTrace(memoryPath))
+	} else {
+		log.Warnf("Memory trace not found. Using %d MiB for all functions.", azure2021DefaultMemoryMiB)
+	}
+
+	return p.extractFunctions(traceFunctions, memoryByHashFunction)
+}
+
+func parsePerInvocationTrace(traceFile string, traceDuration int) []*azure2021Function {
+	log.Infof("Parsing per-invocation trace %s (duration: %d min)", traceFile, traceDuration)
+
+	traceDuration = common.MaxOf(traceDuration, 1)
+	traceEnd := float64(traceDuration) * 60 * common.OneSecondInMicroseconds
+
+	var result []*azure2021Function
+	functionIndex := make(map[string]int)
+
+	csvfile, err := os.Open(traceFile)
+	if err != nil {
+		log.Fatal("Failed to open per-invocation trace file.", err)
+	}
+	defer csvfile.Close()
+
+	reader := csv.NewReader(csvfile)
+
+	appIndex, functionColumnIndex, endTimestampIndex, durationIndex := -1, -1, -1, -1
+
+	for rowID := 0; ; rowID++ {
+		record, err := reader.Read()
+
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			log.Fatal(err)
+		}
+
+		if rowID == 0 {
+			// Parse header
+			for i := 0; i < len(record); i++ {
+				switch strings.ToLower(strings.TrimSpace(record[i])) {
+				case "app":
+					appIndex = i
+				case "func":
+					functionColumnIndex = i
+				case "end_timestamp":
+					endTimestampIndex = i
+				case "duration":
+					durationIndex = i
+				}
+			}
+
+			if appIndex == -1 || functionColumnIndex == -1 || endTimestampIndex == -1 || durationIndex == -1 {
+				log.Fatal("Per-invocation trace does not contain at least one of the columns app, func, end_timestamp, duration.")
+			}
+
+			continue
+		}
+
+		end, err := strconv.ParseFloat(record[endTimestampIndex], 64)
+		common.Check(err)
+		duration, err := strconv.ParseFloat(record[durationIndex], 64)
+		common.Check(err)
+
+		// timestamps in the trace are in seconds
+		arrival := (end - duration) * common.OneSecondInMicroseconds
+		if arrival < 0 || arrival >= traceEnd {
+			continue
+		}
+
+		key := record[appIndex] + record[functionColumnIndex]
+		index, ok := functionIndex[key]
+		if !ok {
+			index = len(result)
+			functionIndex[key] = index
+
+			result = append(result, &azure2021Function{
+				hashApp:      record[appIndex],
+				hashFunction: record[functionColumnIndex],
+			})
+		}
+
+		result[index].invocations = append(result[index].invocations, azure2021Invocation{
+			arrival:  arrival,
+			duration: duration * 1000,
+		})
+	}
+
+	// the trace is sorted by end timestamp, which does not imply ordering by arrival
+	for _, function := range result {
+		sort.SliceStable(function.invocations, func(i, j int) bool {
+			return function.invocations[i].arrival < function.invocations[j].arrival
+		})
+	}
+
+	return result
+}
+
+func (p *Azure2021TraceParser) extractFunctions(traceFunctions []*azure2021Function, memoryByHashFunction map[string]*common.FunctionMemoryStats) []*common.Function {
+	var result []*common.Function
+
+	for i, traceFunction := range traceFunctions {
+		memoryStats, ok := memoryByHashFunction[traceFunction.hashFunction]
+		if !ok {
+			memoryStats = createDefaultMemoryStats(traceFunction)
+		}
+
+		memory := common.MinOf(common.MaxMemQuotaMib, common.MaxOf(common.MinMemQuotaMib, int(memoryStats.Percentile50)))
+		specification := createPerInvocationSpecification(traceFunction.invocations, memory, p.duration)
+
+		function := &common.Function{
+			Name: fmt.Sprintf("%s-%d-%d", common.FunctionNamePrefix, i, p.functionNameGenerator.Uint64()),
+
+			InvocationStats: &common.FunctionInvocationStats{
+				HashApp:      traceFunction.hashApp,
+				HashFunction: traceFunction.hashFunction,
+				Invocations:  append([]int{}, specification.PerMinuteCount...),
+			},
+			RuntimeStats: createAzure2021RuntimeStats(traceFunction),
+			MemoryStats:  memoryStats,
+
+			ColdStartBusyLoopMs: generator.ComputeBusyLoopPeriod(memory),
+
+			Specification: specification,
+		}
+
+		result = append(result, function)
+	}
+
+	return result
+}
+
+func createPerInvocationSpecification(invocations []azure2021Invocation, memory int, traceDuration int) *common.FunctionSpecification {
+	traceDuration = common.MaxOf(traceDuration, 1)
+
+	iat := make(common.IATArray, len(invocations))
+	perMinuteCount := make([]int, traceDuration)
+	runtimeSpecification := make(common.RuntimeSpecificationArray, len(invocations))
+
+	previousArrival := 0.0
+	for i, invocation := range invocations {
+		// the first IAT is the offset from the beginning of the experiment
+		iat[i] = invocation.arrival - previousArrival
+		previousArrival = invocation.arrival
+
+		minute := int(invocation.arrival / (60 * common.OneSecondInMicroseconds))
+		perMinuteCount[common.MinOf(minute, traceDuration-1)]++
+
+		runtimeSpecification[i] = common.RuntimeSpecification{
+			Runtime: common.MinOf(common.MaxExecTimeMilli, common.MaxOf(common.MinExecTimeMilli, int(invocation.duration))),
+			Memory:  memory,
+		}
+	}
+
+	return &common.FunctionSpecification{
+		IAT:                  iat,
+		PerMinuteCount:       perMinuteCount,
+		RuntimeSpecification: runtimeSpecification,
+	}
+}
+
+func createAzure2021RuntimeStats(function *azure2021Function) *common.FunctionRuntimeStats {
+	durations := make([]float64, len(function.invocations))
+	for i, invocation := range function.invocations {
+		durations[i] = invocation.duration
+	}
+
+	stats := createRuntimeStats(durations)
+	stats.HashApp = function.hashApp
+	stats.HashFunction = function.hashFunction
+
+	return stats
+}
+
+func createDefaultMemoryStats(function *azure2021Function) *common.FunctionMemoryStats {
+	return &common.FunctionMemoryStats{
+		HashApp:      function.hashApp,
+		HashFunction: function.hashFunction,
+
+		Count:   float64(len(function.invocations)),
+		Average: azure2021DefaultMemoryMiB,
+
+		Percentile1:   azure2021DefaultMemoryMiB,
+		Percentile5:   azure2021DefaultMemoryMiB,
+		Percentile25:  azure2021DefaultMemoryMiB,
+		Percentile50:  azure2021DefaultMemoryMiB,
+		Percentile75:  azure2021DefaultMemoryMiB,
+		Percentile95:  azure2021DefaultMemoryMiB,
+		Percentile99:  azure2021DefaultMemoryMiB,
+		Percentile100: azure2021DefaultMemoryMiB,
+	}
+}
@@ -0,0 +1,98 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package trace
+
+import (
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+func TestAzure2021TraceParser(t *testing.T) {
+	functions := NewAzure2021Parser("test_data/azure_2021", 2).Parse()
+
+	if len(functions) != 2 {
+		t.Fatalf("Expected 2 functions, got %d.", len(functions))
+	}
+
+	f1, f2 := functions[0], functions[1]
+	if f1.InvocationStats.HashApp != "a172cedcae47474b615c54d510a5d84a8dea3032e958587430b413538be3f333" ||
+		f1.InvocationStats.HashFunction != "3f524cdc07a11d7c6220bdb049fe8dd41b27483c96cc59b581e022d547290d69" ||
+		f2.InvocationStats.HashFunction != "e4ab4e3b1493d5a997b4e51cdefbaa10570ef3ea9432bd72e7b6a89654ceb7f6" {
+
+		t.Error("Unexpected function hashes.")
+	}
+
+	tests := []struct {
+		function       *common.Function
+		iat            []float64
+		perMinuteCount []int
+		runtime        []int
+	}{
+		{
+			function:       f1,
+			iat:            []float64{1_000_000, 29_000_000, 30_000_000, 1_999_500},
+			perMinuteCount: []int{2, 2},
+			runtime:        []int{500, 200, 1000, 1},
+		},
+		{
+			function:       f2,
+			iat:            []float64{10_000_000, 50_000_000},
+			perMinuteCount: []int{1, 1},
+			runtime:        []int{100, 40_000},
+		},
+	}
+
+	for _, test := range tests {
+		spec := test.function.Specification
+
+		if len(spec.IAT) != len(test.iat) || len(spec.RuntimeSpecification) != len(test.runtime) {
+			t.Fatalf("Unexpected number of invocations for %s.", test.function.Name)
+		}
+
+		for i := 0; i < len(test.iat); i++ {
+			if !floatEqual(spec.IAT[i], test.iat[i]) {
+				t.Errorf("Unexpected IAT %d of %s - got %f, expected %f.", i, test.function.Name, spec.IAT[i], test.iat[i])
+			}
+
+			if spec.RuntimeSpecification[i].Runtime != test.runtime[i] || spec.RuntimeSpecification[i].Memory != azure2021DefaultMemoryMiB {
+				t.Errorf("Unexpected runtime specification %d of %s.", i, test.function.Name)
+			}
+		}
+
+		for i := 0; i < len(test.perMinuteCount); i++ {
+			if spec.PerMinuteCount[i] != test.perMinuteCount[i] || test.function.InvocationStats.Invocations[i] != test.perMinuteCount[i] {
+				t.Errorf("Unexpected per-minute count of %s.", test.function.Name)
+			}
+		}
+	}
+
+	if !floatEqual(f2.RuntimeStats.Average, 20_050) || !floatEqual(f2.RuntimeStats.Count, 2) ||
+		!floatEqual(f2.RuntimeStats.Percentile0, 100) || !floatEqual(f2.RuntimeStats.Percentile100, 40_000) ||
+		!floatEqual(f1.RuntimeStats.Percentile50, 200) {
+
+		t.Error("Unexpected runtime statistics.")
+	}
+}
@@ -29,6 +29,7 @@ import (
 	"fmt"
 	"github.com/gocarina/gocsv"
 	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
 	"github.com/vhive-serverless/loader/pkg/generator"
 	"io"
 	"math/rand"
@@ -43,6 +44,20 @@ import (
 type AzureTraceParser struct {
 	DirectoryPath string
 
+	// Day files of the trace in chronological order. If empty, invocations.csv, durations.csv and memory.csv from the
+	// directory are used.
+	InvocationFiles []string
+	RuntimeFiles    []string
+	MemoryFiles     []string
+
+	// StartMinute is the offset of the replay from the beginning of the first invocation file
+	StartMinute int
+	// StatsMergePolicy selects how runtime and memory statistics of a function are merged across days
+	StatsMergePolicy string
+	// Streaming only indexes the rows of the invocation files, whose invocations are then read in windows of minutes
+	// as the experiment advances
+	Streaming bool
+
 	duration              int
 	functionNameGenerator *rand.Rand
 }
@@ -56,6 +71,21 @@ func NewAzureParser(directoryPath string, totalDuration int) *AzureTraceParser {
 	}
 }
 
+// NewAzureMultiDayParser creates a parser for the trace day files and replay offset selected in the configuration
+func NewAzureMultiDayParser(directoryPath string, totalDuration int, cfg *config.LoaderConfiguration) *AzureTraceParser {
+	parser := NewAzureParser(directoryPath, totalDuration)
+
+	parser.InvocationFiles = resolveTraceFiles(directoryPath, cfg.InvocationTraceFiles)
+	parser.RuntimeFiles = resolveTraceFiles(directoryPath, cfg.DurationTraceFiles)
+	parser.MemoryFiles = resolveTraceFiles(directoryPath, cfg.MemoryTraceFiles)
+
+	parser.StartMinute = cfg.TraceStartDay*MinutesPerDay + cfg.TraceStartMinute
+	parser.StatsMergePolicy = cfg.TraceStatsMergePolicy
+	parser.Streaming = cfg.StreamingSpecification
+
+	return parser
+}
+
 func createRuntimeMap(runtime *[]common.FunctionRuntimeStats) map[string]*common.FunctionRuntimeStats {
 	result := make(map[string]*common.FunctionRuntimeStats)
 
@@ -114,39 +144,98 @@ func (p *AzureTraceParser) extractFunctions(invocations *[]common.FunctionInvoca
 }
 
 func (p *AzureTraceParser) Parse() []*common.Function {
-	invocationPath := p.DirectoryPath + "/invocations.csv"
-	runtimePath := p.DirectoryPath + "/durations.csv"
-	memoryPath := p.DirectoryPath + "/memory.csv"
+	invocationFiles := defaultTraceFiles(p.InvocationFiles, p.DirectoryPath+"/invocations.csv")
+	runtimeFiles := defaultTraceFiles(p.RuntimeFiles, p.DirectoryPath+"/durations.csv")
+	memoryFiles := defaultTraceFiles(p.MemoryFiles, p.DirectoryPath+"/memory.csv")
+
+	var invocationTrace *[]common.FunctionInvocationStats
+	if p.Streaming {
+		invocationTrace = indexInvocationTraces(invocationFiles, p.StartMinute, p.duration)
+	} else {
+		invocationTrace = parseInvocationTraces(invocationFiles, p.StartMinute, p.duration)
+	}
 
-	invocationTrace := parseInvocationTrace(invocationPath, p.duration)
-	runtimeTrace := parseRuntimeTrace(runtimePath)
-	memoryTrace := parseMemoryTrace(memoryPath)
+	var runtimeTraces []*[]common.FunctionRuntimeStats
+	for _, file := range runtimeFiles {
+		runtimeTraces = append(runtimeTraces, parseRuntimeTrace(file))
+	}
+
+	var memoryTraces []*[]common.FunctionMemoryStats
+	for _, file := range memoryFiles {
+		memoryTraces = append(memoryTraces, parseMemoryTrace(file))
+	}
+
+	runtimeTrace := mergeRuntimeTraces(runtimeTraces, p.StatsMergePolicy)
+	memoryTrace := mergeMemoryTraces(memoryTraces, p.StatsMergePolicy)
 
 	return p.extractFunctions(invocationTrace, runtimeTrace, memoryTrace)
 }
 
 func parseInvocationTrace(traceFile string, traceDuration int) *[]common.FunctionInvocationStats {
-	log.Infof("Parsing function invocation trace %s (duration: %d min)", traceFile, traceDuration)
+	return parseInvocationTraces([]string{traceFile}, 0, traceDuration)
+}
+
+// parseInvocationTraces concatenates the invocations of each function, identified by its hash triple, across the day
+// files and returns traceDuration minutes starting from startMinute. Functions missing on a day have no invocations
+// on that day.
+func parseInvocationTraces(traceFiles []string, startMinute int, traceDuration int) *[]common.FunctionInvocationStats {
+	log.Infof("Parsing function invocation trace %v (start: %d min, duration: %d min)", traceFiles, startMinute, traceDuration)
+
+	return readInvocationTraces(traceFiles, startMinute, traceDuration, false)
+}
+
+// indexInvocationTraces reads only the hashes of the functions and the position of their rows in the day files, from
+// which the invocations are read in windows by the InvocationWindowReader of each function
+func indexInvocationTraces(traceFiles []string, startMinute int, traceDuration int) *[]common.FunctionInvocationStats {
+	log.Infof("Indexing function invocation trace %v (start: %d min, duration: %d min)", traceFiles, startMinute, traceDuration)
+
+	return readInvocationTraces(traceFiles, startMinute, traceDuration, true)
+}
 
-	// Fit duration on (0, 1440] interval
-	traceDuration = common.MaxOf(common.MinOf(traceDuration, 1440), 1)
+func readInvocationTraces(traceFiles []string, startMinute int, traceDuration int, index bool) *[]common.FunctionInvocationStats {
+
+	traceDuration = common.MaxOf(traceDuration, 1)
+	startMinute = common.MaxOf(startMinute, 0)
+	endMinute := startMinute + traceDuration
 
 	var result []common.FunctionInvocationStats
+	functionIndex := make(map[string]int)
+
+	fileStart := 0
+	for _, traceFile := range traceFiles {
+		if fileStart >= endMinute {
+			break
+		}
+
+		fileStart += parseInvocationDay(traceFile, fileStart, startMinute, endMinute, &result, functionIndex, index)
+	}
+
+	if fileStart < endMinute {
+		log.Warnf("Invocation trace contains %d minutes, which is less than requested. Missing minutes have no invocations.", fileStart)
+	}
+
+	return &result
+}
 
-	invocationIndices := make([][]int, traceDuration)
-	totalInvocations := make([]int, traceDuration)
+// parseInvocationDay reads the minutes of the file beginning at fileStart that overlap [startMinute, endMinute) and
+// returns the number of minutes in the file. If index is set, only the positions of the rows are recorded.
+func parseInvocationDay(traceFile string, fileStart int, startMinute int, endMinute int,
+	result *[]common.FunctionInvocationStats, functionIndex map[string]int, index bool) int {
 
 	csvfile, err := os.Open(traceFile)
 	if err != nil {
 		log.Fatal("Failed to open invocation CSV file.", err)
 	}
+	defer csvfile.Close()
 
 	reader := csv.NewReader(csvfile)
 
 	rowID := -1
+	minutesInFile := 0
 	hashOwnerIndex, hashAppIndex, hashFunctionIndex, invocationColumnIndex := -1, -1, -1, -1
 
 	for {
+		offset := reader.InputOffset()
 		record, err := reader.Read()
 
 		if err != nil {
@@ -166,7 +255,7 @@ func parseInvocationTrace(traceFile string, traceDuration int) *[]common.Functio
 					hashAppIndex = i
 				case "hashfunction":
 					hashFunctionIndex = i
-				case "trigger": //! Unused field.
+				case "trigger": // determines the arrival model of the function
 					invocationColumnIndex = i + 1
 				}
 			}
@@ -178,36 +267,60 @@ func parseInvocationTrace(traceFile string, traceDuration int) *[]common.Functio
 			if invocationColumnIndex == -1 {
 				invocationColumnIndex = 3
 			}
+
+			minutesInFile = len(record) - invocationColumnIndex
+			if fileStart+minutesInFile <= startMinute {
+				// the whole day is before the replay window
+				return minutesInFile
+			}
 		} else {
 			// Parse invocations
-			var invocations []int
-
-			for i := invocationColumnIndex; i < invocationColumnIndex+traceDuration; i++ {
-				minute := i - invocationColumnIndex
-				num, err := strconv.Atoi(record[i])
-				common.Check(err)
+			key := record[hashOwnerIndex] + record[hashAppIndex] + record[hashFunctionIndex]
+
+			i, ok := functionIndex[key]
+			if !ok {
+				i = len(*result)
+				functionIndex[key] = i
+
+				stats := common.FunctionInvocationStats{
+					HashOwner:    record[hashOwnerIndex],
+					HashApp:      record[hashAppIndex],
+					HashFunction: record[hashFunctionIndex],
+					Trigger:      record[invocationColumnIndex-1],
+				}
+				if index {
+					stats.Windows = &azureInvocationWindows{startMinute: startMinute, duration: endMinute - startMinute}
+				} else {
+					stats.Invocations = make([]int, endMinute-startMinute)
+				}
 
-				invocations = append(invocations, num)
+				*result = append(*result, stats)
+			}
 
-				for j := 0; j < num; j++ {
-					invocationIndices[minute] = append(invocationIndices[minute], rowID)
+			if index {
+				windows := (*result)[i].Windows.(*azureInvocationWindows)
+				windows.rows = append(windows.rows, invocationRow{
+					traceFile: traceFile,
+					offset:    offset,
+					fileStart: fileStart,
+					minutes:   minutesInFile,
+					column:    invocationColumnIndex,
+				})
+			} else {
+				invocations := (*result)[i].Invocations
+				for minute := common.MaxOf(fileStart, startMinute); minute < common.MinOf(fileStart+minutesInFile, endMinute); minute++ {
+					num, err := strconv.Atoi(record[invocationColumnIndex+minute-fileStart])
+					common.Check(err)
+
+					invocations[minute-startMinute] += num
 				}
-				totalInvocations[minute] = totalInvocations[minute] + num
 			}
-
-			result = append(result, common.FunctionInvocationStats{
-				HashOwner:    record[hashOwnerIndex],
-				HashApp:      record[hashAppIndex],
-				HashFunction: record[hashFunctionIndex],
-				Trigger:      record[invocationColumnIndex-1],
-				Invocations:  invocations,
-			})
 		}
 
 		rowID++
 	}
 
-	return &result
+	return minutesInFile
 }
 
 func parseRuntimeTrace(traceFile string) *[]common.FunctionRuntimeStats {
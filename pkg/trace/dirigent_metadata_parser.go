@@ -8,22 +8,57 @@ import (
 	"strings"
 )
 
-type DirigentMetadataParser struct {
+// MetadataEnricher attaches platform-specific metadata to the functions returned by a TraceParser
+type MetadataEnricher interface {
+	Enrich(functions []*common.Function)
+}
+
+// DirigentJSONEnricher attaches metadata from dirigent.json in the trace directory when running on Dirigent
+type DirigentJSONEnricher struct {
 	directoryPath string
-	functions     []*common.Function
-	yamlPath      string
 	platform      string
 }
 
-func NewDirigentMetadataParser(directoryPath string, functions []*common.Function, yamlPath string, platform string) *DirigentMetadataParser {
-	return &DirigentMetadataParser{
+// KnativeYAMLEnricher attaches metadata converted from the Knative service YAML when running on Knative
+type KnativeYAMLEnricher struct {
+	yamlPath string
+	platform string
+}
+
+type DirigentMetadataParser struct {
+	functions []*common.Function
+	enrichers []MetadataEnricher
+}
+
+func NewDirigentJSONEnricher(directoryPath string, platform string) *DirigentJSONEnricher {
+	return &DirigentJSONEnricher{
 		directoryPath: directoryPath,
-		functions:     functions,
-		yamlPath:      yamlPath,
 		platform:      platform,
 	}
 }
 
+func NewKnativeYAMLEnricher(yamlPath string, platform string) *KnativeYAMLEnricher {
+	return &KnativeYAMLEnricher{
+		yamlPath: yamlPath,
+		platform: platform,
+	}
+}
+
+// NewMetadataEnrichers returns the default chain of enrichers used by the loader
+func NewMetadataEnrichers(directoryPath string, yamlPath string, platform string) []MetadataEnricher {
+	return []MetadataEnricher{
+		NewDirigentJSONEnricher(directoryPath, platform),
+		NewKnativeYAMLEnricher(yamlPath, platform),
+	}
+}
+
+func NewDirigentMetadataParser(directoryPath string, functions []*common.Function, yamlPath string, platform string) *DirigentMetadataParser {
+	return &DirigentMetadataParser{
+		functions: functions,
+		enrichers: NewMetadataEnrichers(directoryPath, yamlPath, platform),
+	}
+}
+
 func readDirigentMetadataJSON(traceFile string, platform string) *[]common.DirigentMetadata {
 	if !strings.Contains(strings.ToLower(platform), "dirigent") {
 		return nil
@@ -46,21 +81,32 @@ func readDirigentMetadataJSON(traceFile string, platform string) *[]common.Dirig
 	return &metadata
 }
 
-func (dmp *DirigentMetadataParser) Parse() {
-	dirigentPath := dmp.directoryPath + "/dirigent.json"
-	dirigentMetadata := readDirigentMetadataJSON(dirigentPath, dmp.platform)
+func (e *DirigentJSONEnricher) Enrich(functions []*common.Function) {
+	dirigentPath := e.directoryPath + "/dirigent.json"
+	dirigentMetadata := readDirigentMetadataJSON(dirigentPath, e.platform)
+	if dirigentMetadata == nil {
+		return
+	}
 
-	var dirigentMetadataByHashFunction map[string]*common.DirigentMetadata
-	if dirigentMetadata != nil {
-		dirigentMetadataByHashFunction = createDirigentMetadataMap(dirigentMetadata)
+	dirigentMetadataByHashFunction := createDirigentMetadataMap(dirigentMetadata)
+	for _, function := range functions {
+		function.DirigentMetadata = dirigentMetadataByHashFunction[function.InvocationStats.HashFunction]
+	}
+}
+
+func (e *KnativeYAMLEnricher) Enrich(functions []*common.Function) {
+	if !strings.Contains(strings.ToLower(e.platform), "knative") {
+		return
 	}
 
-	for _, function := range dmp.functions {
-		if dirigentMetadata != nil {
-			function.DirigentMetadata = dirigentMetadataByHashFunction[function.InvocationStats.HashFunction]
-		} else if strings.Contains(strings.ToLower(dmp.platform), "knative") {
-			// values are not used for Knative so they are irrelevant
-			function.DirigentMetadata = convertKnativeYamlToDirigentMetadata(dmp.yamlPath)
-		}
+	// values are not used for Knative so they are irrelevant
+	for _, function := range functions {
+		function.DirigentMetadata = convertKnativeYamlToDirigentMetadata(e.yamlPath)
+	}
+}
+
+func (dmp *DirigentMetadataParser) Parse() {
+	for _, enricher := range dmp.enrichers {
+		enricher.Enrich(dmp.functions)
 	}
 }
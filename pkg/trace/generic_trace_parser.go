@@ -0,0 +1,219 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package trace
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/generator"
+)
+
+// Fields of the generic CSV trace that can be mapped onto arbitrary column names using TraceColumnMapping
+const (
+	ColumnHashOwner    = "HashOwner"
+	ColumnHashApp      = "HashApp"
+	ColumnHashFunction = "HashFunction"
+	ColumnTrigger      = "Trigger"
+	ColumnTime         = "Time"
+	ColumnInvocations  = "Invocations"
+	ColumnRuntime      = "Runtime"
+	ColumnMemory       = "Memory"
+)
+
+// GenericCSVParser parses trace.csv with one row per function and time unit. Each row contains the index of the time
+// unit (minute or second depending on the trace granularity), the number of invocations and optionally the average
+// runtime (ms) and memory (MiB) in that time unit. Column names default to the field names.
+type GenericCSVParser struct {
+	DirectoryPath string
+
+	duration              int
+	columnMapping         map[string]string
+	functionNameGenerator *rand.Rand
+}
+
+type genericFunction struct {
+	invocationStats *common.FunctionInvocationStats
+
+	runtime []float64
+	memory  []float64
+}
+
+func NewGenericCSVParser(directoryPath string, totalDuration int, columnMapping map[string]string) *GenericCSVParser {
+	return &GenericCSVParser{
+		DirectoryPath: directoryPath,
+
+		duration:              totalDuration,
+		columnMapping:         columnMapping,
+		functionNameGenerator: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+func (p *GenericCSVParser) columnName(field string) string {
+	if name, ok := p.columnMapping[field]; ok {
+		return name
+	}
+
+	return field
+}
+
+func (p *GenericCSVParser) Parse() []*common.Function {
+	traceFile := p.DirectoryPath + "/trace.csv"
+	duration := common.MaxOf(p.duration, 1)
+
+	log.Infof("Parsing generic CSV trace %s (duration: %d)", traceFile, duration)
+
+	csvfile, err := os.Open(traceFile)
+	if err != nil {
+		log.Fatal("Failed to open generic CSV trace file.", err)
+	}
+	defer csvfile.Close()
+
+	reader := csv.NewReader(csvfile)
+
+	header, err := reader.Read()
+	if err != nil {
+		log.Fatal("Failed to read the header of the generic CSV trace.", err)
+	}
+
+	columnIndex := make(map[string]int)
+	for _, field := range []string{ColumnHashOwner, ColumnHashApp, ColumnHashFunction, ColumnTrigger, ColumnTime, ColumnInvocations, ColumnRuntime, ColumnMemory} {
+		columnIndex[field] = -1
+
+		for i := 0; i < len(header); i++ {
+			if strings.EqualFold(strings.TrimSpace(header[i]), p.columnName(field)) {
+				columnIndex[field] = i
+			}
+		}
+	}
+
+	for _, field := range []string{ColumnHashFunction, ColumnTime, ColumnInvocations} {
+		if columnIndex[field] == -1 {
+			log.Fatalf("Generic CSV trace does not contain column '%s' for field %s.", p.columnName(field), field)
+		}
+	}
+	if columnIndex[ColumnRuntime] == -1 || columnIndex[ColumnMemory] == -1 {
+		log.Warn("Generic CSV trace does not contain runtime or memory columns. Specifications cannot be generated from it.")
+	}
+
+	cell := func(record []string, field string) string {
+		if columnIndex[field] == -1 {
+			return ""
+		}
+
+		return strings.TrimSpace(record[columnIndex[field]])
+	}
+
+	var traceFunctions []*genericFunction
+	functionIndex := make(map[string]int)
+
+	for {
+		record, err := reader.Read()
+
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			log.Fatal(err)
+		}
+
+		hashFunction := cell(record, ColumnHashFunction)
+		index, ok := functionIndex[hashFunction]
+		if !ok {
+			index = len(traceFunctions)
+			functionIndex[hashFunction] = index
+
+			traceFunctions = append(traceFunctions, &genericFunction{
+				invocationStats: &common.FunctionInvocationStats{
+					HashOwner:    cell(record, ColumnHashOwner),
+					HashApp:      cell(record, ColumnHashApp),
+					HashFunction: hashFunction,
+					Trigger:      cell(record, ColumnTrigger),
+					Invocations:  make([]int, duration),
+				},
+			})
+		}
+		function := traceFunctions[index]
+
+		timeUnit, err := strconv.Atoi(cell(record, ColumnTime))
+		common.Check(err)
+		invocations, err := strconv.Atoi(cell(record, ColumnInvocations))
+		common.Check(err)
+
+		if timeUnit >= 0 && timeUnit < duration {
+			function.invocationStats.Invocations[timeUnit] += invocations
+		}
+
+		if value := cell(record, ColumnRuntime); value != "" && invocations > 0 {
+			runtime, err := strconv.ParseFloat(value, 64)
+			common.Check(err)
+
+			function.runtime = append(function.runtime, runtime)
+		}
+
+		if value := cell(record, ColumnMemory); value != "" && invocations > 0 {
+			memory, err := strconv.ParseFloat(value, 64)
+			common.Check(err)
+
+			function.memory = append(function.memory, memory)
+		}
+	}
+
+	var result []*common.Function
+
+	gen := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	for i, traceFunction := range traceFunctions {
+		invocationStats := traceFunction.invocationStats
+
+		runtimeStats := createRuntimeStats(traceFunction.runtime)
+		runtimeStats.HashOwner, runtimeStats.HashApp, runtimeStats.HashFunction = invocationStats.HashOwner, invocationStats.HashApp, invocationStats.HashFunction
+
+		memoryStats := createMemoryStats(traceFunction.memory)
+		memoryStats.HashOwner, memoryStats.HashApp, memoryStats.HashFunction = invocationStats.HashOwner, invocationStats.HashApp, invocationStats.HashFunction
+
+		function := &common.Function{
+			Name: fmt.Sprintf("%s-%d-%d", common.FunctionNamePrefix, i, p.functionNameGenerator.Uint64()),
+
+			InvocationStats: invocationStats,
+			RuntimeStats:    runtimeStats,
+			MemoryStats:     memoryStats,
+
+			ColdStartBusyLoopMs: generator.ComputeBusyLoopPeriod(generator.GenerateMemorySpec(gen, gen.Float64(), memoryStats)),
+		}
+
+		result = append(result, function)
+	}
+
+	return result
+}
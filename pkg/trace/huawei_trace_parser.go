@@ -0,0 +1,234 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package trace
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/generator"
+)
+
+// HuaweiTraceParser parses the Huawei Cloud public serverless trace
+// (https://github.com/sir-lab/data-release), where each metric is stored in a separate file with one row per time
+// unit and one column per function. Requests are read at the granularity of the experiment, whereas function
+// execution time, memory usage and CPU usage are summarized from the per-minute files.
+type HuaweiTraceParser struct {
+	DirectoryPath string
+
+	duration              int
+	granularity           common.TraceGranularity
+	functionNameGenerator *rand.Rand
+}
+
+func NewHuaweiParser(directoryPath string, totalDuration int, granularity common.TraceGranularity) *HuaweiTraceParser {
+	return &HuaweiTraceParser{
+		DirectoryPath: directoryPath,
+
+		duration:              totalDuration,
+		granularity:           granularity,
+		functionNameGenerator: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+func (p *HuaweiTraceParser) Parse() []*common.Function {
+	requestPath := p.DirectoryPath + "/requests_minute.csv"
+	if p.granularity == common.SecondGranularity {
+		requestPath = p.DirectoryPath + "/requests_second.csv"
+	}
+	runtimePath := p.DirectoryPath + "/function_delay_minute.csv"
+	memoryPath := p.DirectoryPath + "/memory_usage_minute.csv"
+	cpuPath := p.DirectoryPath + "/cpu_usage_minute.csv"
+
+	log.Infof("Parsing Huawei request trace %s (duration: %d)", requestPath, p.duration)
+	functionIDs, requests := parseWideTrace(requestPath, common.MaxOf(p.duration, 1))
+
+	log.Infof("Parsing Huawei function delay trace: %s", runtimePath)
+	runtimeIDs, runtime := parseWideTrace(runtimePath, -1)
+
+	log.Infof("Parsing Huawei memory usage trace: %s", memoryPath)
+	memoryIDs, memory := parseWideTrace(memoryPath, -1)
+
+	runtimeByFunctionID := make(map[string][]float64)
+	for i, id := range runtimeIDs {
+		runtimeByFunctionID[id] = runtime[i]
+	}
+
+	memoryByFunctionID := make(map[string][]float64)
+	for i, id := range memoryIDs {
+		memoryByFunctionID[id] = memory[i]
+	}
+
+	cpuByFunctionID := make(map[string][]float64)
+	if _, err := os.Stat(cpuPath); err == nil {
+		log.Infof("Parsing Huawei CPU usage trace: %s", cpuPath)
+		cpuIDs, cpu := parseWideTrace(cpuPath, -1)
+
+		for i, id := range cpuIDs {
+			cpuByFunctionID[id] = cpu[i]
+		}
+	}
+
+	var result []*common.Function
+
+	gen := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	for i, id := range functionIDs {
+		// the statistics of functions without any samples could not be used to generate runtime specifications
+		if len(runtimeByFunctionID[id]) == 0 || len(memoryByFunctionID[id]) == 0 {
+			log.Warnf("Skipping function %s, which has no function delay or memory usage samples in the Huawei trace.", id)
+			continue
+		}
+
+		invocations := make([]int, len(requests[i]))
+		for j := 0; j < len(requests[i]); j++ {
+			invocations[j] = int(requests[i][j])
+		}
+
+		// the trace contains neither owners nor applications, so each function is treated as a separate application
+		invocationStats := &common.FunctionInvocationStats{
+			HashApp:      id,
+			HashFunction: id,
+			Invocations:  invocations,
+		}
+
+		runtimeStats := createRuntimeStats(runtimeByFunctionID[id])
+		runtimeStats.HashApp, runtimeStats.HashFunction = id, id
+
+		memoryStats := createMemoryStats(memoryByFunctionID[id])
+		memoryStats.HashApp, memoryStats.HashFunction = id, id
+
+		function := &common.Function{
+			Name: fmt.Sprintf("%s-%d-%d", common.FunctionNamePrefix, len(result), p.functionNameGenerator.Uint64()),
+
+			InvocationStats: invocationStats,
+			RuntimeStats:    runtimeStats,
+			MemoryStats:     memoryStats,
+
+			ColdStartBusyLoopMs: generator.ComputeBusyLoopPeriod(generator.GenerateMemorySpec(gen, gen.Float64(), memoryStats)),
+
+			CPURequestsMilli: cpuRequestsMilli(cpuByFunctionID[id]),
+		}
+
+		result = append(result, function)
+	}
+
+	return result
+}
+
+// cpuRequestsMilli returns the average CPU usage of the function in millicores, given the samples in cores, or zero if
+// the function has no samples
+func cpuRequestsMilli(samples []float64) int {
+	if len(samples) == 0 {
+		return 0
+	}
+
+	sum := 0.0
+	for _, sample := range samples {
+		sum += sample
+	}
+
+	return int(math.Ceil(sum / float64(len(samples)) * 1000))
+}
+
+// parseWideTrace reads a trace with one row per time unit and one column per function, skipping columns 'day' and
+// 'time'. With rows == -1 the whole file is read as a metric and only positive cells are kept as samples, otherwise
+// the first rows are read as request counts, with missing cells counted as zero. The result is indexed by function.
+func parseWideTrace(traceFile string, rows int) ([]string, [][]float64) {
+	csvfile, err := os.Open(traceFile)
+	if err != nil {
+		log.Fatal("Failed to open Huawei trace file.", err)
+	}
+	defer csvfile.Close()
+
+	reader := csv.NewReader(csvfile)
+
+	var functionIDs []string
+	var columns []int
+	var result [][]float64
+
+	for rowID := -1; rows < 0 || rowID < rows; rowID++ {
+		record, err := reader.Read()
+
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			log.Fatal(err)
+		}
+
+		if rowID == -1 {
+			// Parse header
+			for i := 0; i < len(record); i++ {
+				switch name := strings.TrimSpace(record[i]); strings.ToLower(name) {
+				case "day", "time", "":
+				default:
+					functionIDs = append(functionIDs, name)
+					columns = append(columns, i)
+				}
+			}
+
+			result = make([][]float64, len(functionIDs))
+			continue
+		}
+
+		for i, column := range columns {
+			cell := strings.TrimSpace(record[column])
+
+			value := 0.0
+			if cell != "" && !strings.EqualFold(cell, "nan") {
+				value, err = strconv.ParseFloat(cell, 64)
+				common.Check(err)
+			}
+
+			if rows < 0 && value <= 0 {
+				// no sample for metrics when the function was not running
+				continue
+			}
+
+			result[i] = append(result[i], value)
+		}
+	}
+
+	if rows > 0 {
+		// the trace is shorter than the experiment
+		for i := range result {
+			for len(result[i]) < rows {
+				result[i] = append(result[i], 0)
+			}
+		}
+	}
+
+	return functionIDs, result
+}
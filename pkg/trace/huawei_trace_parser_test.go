@@ -0,0 +1,106 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package trace
+
+import (
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+func TestHuaweiTraceParser(t *testing.T) {
+	tests := []struct {
+		testName    string
+		duration    int
+		granularity common.TraceGranularity
+		invocations [][]int
+	}{
+		{
+			testName:    "minute_granularity",
+			duration:    2,
+			granularity: common.MinuteGranularity,
+			invocations: [][]int{{5, 3}, {0, 2}},
+		},
+		{
+			testName:    "minute_granularity_longer_than_trace",
+			duration:    4,
+			granularity: common.MinuteGranularity,
+			invocations: [][]int{{5, 3, 0, 0}, {0, 2, 1, 0}},
+		},
+		{
+			testName:    "second_granularity",
+			duration:    3,
+			granularity: common.SecondGranularity,
+			invocations: [][]int{{1, 2, 0}, {0, 1, 0}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			functions := NewHuaweiParser("test_data/huawei", test.duration, test.granularity).Parse()
+
+			// function 2 has requests, but neither function delay nor memory usage samples
+			if len(functions) != 2 {
+				t.Fatalf("Expected 2 functions, got %d.", len(functions))
+			}
+
+			for i, function := range functions {
+				if len(function.InvocationStats.Invocations) != len(test.invocations[i]) {
+					t.Fatalf("Unexpected length of the invocation trace of function %d.", i)
+				}
+
+				for j := 0; j < len(test.invocations[i]); j++ {
+					if function.InvocationStats.Invocations[j] != test.invocations[i][j] {
+						t.Errorf("Unexpected number of invocations of function %d in time unit %d.", i, j)
+					}
+				}
+			}
+		})
+	}
+
+	functions := NewHuaweiParser("test_data/huawei", 1, common.MinuteGranularity).Parse()
+	f0, f1 := functions[0], functions[1]
+
+	if f0.InvocationStats.HashFunction != "0" || f1.InvocationStats.HashFunction != "1" || f1.RuntimeStats.HashFunction != "1" {
+		t.Error("Unexpected function identifiers.")
+	}
+
+	if !floatEqual(f0.RuntimeStats.Average, 15.5) || !floatEqual(f0.RuntimeStats.Count, 2) ||
+		!floatEqual(f0.RuntimeStats.Percentile50, 10.5) || !floatEqual(f1.RuntimeStats.Minimum, 100) ||
+		!floatEqual(f1.RuntimeStats.Percentile100, 300) {
+
+		t.Error("Unexpected runtime statistics.")
+	}
+
+	if !floatEqual(f0.MemoryStats.Average, 192) || !floatEqual(f0.MemoryStats.Percentile1, 128) ||
+		!floatEqual(f0.MemoryStats.Percentile100, 256) || !floatEqual(f1.MemoryStats.Percentile50, 512) {
+
+		t.Error("Unexpected memory statistics.")
+	}
+
+	if f0.CPURequestsMilli != 500 || f1.CPURequestsMilli != 1500 {
+		t.Errorf("Expected CPU requests of 500 and 1500 millicores, got %d and %d.", f0.CPURequestsMilli, f1.CPURequestsMilli)
+	}
+}
@@ -0,0 +1,103 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package trace
+
+import (
+	"encoding/csv"
+	"io"
+	"os"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+// invocationRow is the position of the row of a function in a day file of the invocation trace
+type invocationRow struct {
+	traceFile string
+	offset    int64
+
+	// first minute of the file relative to the beginning of the first day file
+	fileStart int
+	minutes   int
+	// index of the column of the first minute
+	column int
+}
+
+// azureInvocationWindows reads the invocations of a function from the rows of the Azure invocation trace on demand,
+// so that only the hashes and the row positions of the functions are kept in memory during the experiment
+type azureInvocationWindows struct {
+	rows []invocationRow
+
+	startMinute int
+	duration    int
+}
+
+func (w *azureInvocationWindows) TimeUnits() int {
+	return w.duration
+}
+
+func (w *azureInvocationWindows) ReadWindow(start int, end int) []int {
+	result := make([]int, end-start)
+
+	// minutes of the window relative to the beginning of the first day file
+	start, end = start+w.startMinute, end+w.startMinute
+
+	for _, row := range w.rows {
+		from, to := common.MaxOf(start, row.fileStart), common.MinOf(end, row.fileStart+row.minutes)
+		if from >= to {
+			continue
+		}
+
+		record := readInvocationRow(row)
+		for minute := from; minute < to; minute++ {
+			num, err := strconv.Atoi(record[row.column+minute-row.fileStart])
+			common.Check(err)
+
+			result[minute-start] += num
+		}
+	}
+
+	return result
+}
+
+func readInvocationRow(row invocationRow) []string {
+	f, err := os.Open(row.traceFile)
+	if err != nil {
+		log.Fatal("Failed to open invocation CSV file.", err)
+	}
+	defer f.Close()
+
+	if _, err = f.Seek(row.offset, io.SeekStart); err != nil {
+		log.Fatalf("Failed to seek to the invocations of a function in %s: %s", row.traceFile, err)
+	}
+
+	record, err := csv.NewReader(f).Read()
+	if err != nil {
+		log.Fatalf("Failed to read the invocations of a function from %s: %s", row.traceFile, err)
+	}
+
+	return record
+}
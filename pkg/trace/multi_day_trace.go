@@ -0,0 +1,217 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package trace
+
+import (
+	"math"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+const MinutesPerDay = 1440
+
+// Policies for merging runtime and memory statistics of a function that appears on multiple days of the trace
+const (
+	StatsMergeFirst   = "first"
+	StatsMergeLast    = "last"
+	StatsMergeAverage = "average" // weighted by Count
+	StatsMergeMax     = "max"
+)
+
+// resolveTraceFiles expands glob patterns, which are relative to the trace directory unless absolute, into a sorted
+// list of files. Patterns without wildcards are taken as they are, preserving their order.
+func resolveTraceFiles(directoryPath string, patterns []string) []string {
+	var result []string
+
+	for _, pattern := range patterns {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(directoryPath, pattern)
+		}
+
+		if !strings.ContainsAny(pattern, "*?[") {
+			result = append(result, pattern)
+			continue
+		}
+
+		matches, err := filepath.Glob(pattern)
+		if err != nil || len(matches) == 0 {
+			log.Fatalf("No trace files match the pattern '%s'.", pattern)
+		}
+
+		// filepath.Glob returns matches in lexical order, e.g., d01, d02, ..., d14
+		result = append(result, matches...)
+	}
+
+	return result
+}
+
+func defaultTraceFiles(files []string, defaultFile string) []string {
+	if len(files) == 0 {
+		return []string{defaultFile}
+	}
+
+	return files
+}
+
+func mergeFields(result []*float64, days [][]*float64, counts []float64, policy string) {
+	switch policy {
+	case StatsMergeFirst:
+		for i := range result {
+			*result[i] = *days[0][i]
+		}
+	case StatsMergeLast:
+		for i := range result {
+			*result[i] = *days[len(days)-1][i]
+		}
+	case StatsMergeMax:
+		for i := range result {
+			*result[i] = *days[0][i]
+			for _, day := range days[1:] {
+				*result[i] = math.Max(*result[i], *day[i])
+			}
+		}
+	case StatsMergeAverage:
+		totalCount := 0.0
+		for _, count := range counts {
+			totalCount += count
+		}
+
+		for i := range result {
+			*result[i] = 0
+			for d, day := range days {
+				if totalCount > 0 {
+					*result[i] += *day[i] * counts[d] / totalCount
+				} else {
+					*result[i] += *day[i] / float64(len(days))
+				}
+			}
+		}
+	default:
+		log.Fatalf("Unsupported trace statistics merge policy '%s'.", policy)
+	}
+}
+
+func runtimeStatsFields(stats *common.FunctionRuntimeStats) []*float64 {
+	return []*float64{&stats.Average, &stats.Minimum, &stats.Maximum, &stats.Percentile0, &stats.Percentile1,
+		&stats.Percentile25, &stats.Percentile50, &stats.Percentile75, &stats.Percentile99, &stats.Percentile100}
+}
+
+func memoryStatsFields(stats *common.FunctionMemoryStats) []*float64 {
+	return []*float64{&stats.Average, &stats.Percentile1, &stats.Percentile5, &stats.Percentile25,
+		&stats.Percentile50, &stats.Percentile75, &stats.Percentile95, &stats.Percentile99, &stats.Percentile100}
+}
+
+func defaultMergePolicy(policy string) string {
+	if policy == "" {
+		return StatsMergeAverage
+	}
+
+	return strings.ToLower(policy)
+}
+
+// mergeRuntimeTraces merges the runtime statistics of each function across days. Count is always summed.
+func mergeRuntimeTraces(traces []*[]common.FunctionRuntimeStats, policy string) *[]common.FunctionRuntimeStats {
+	if len(traces) == 1 {
+		return traces[0]
+	}
+	policy = defaultMergePolicy(policy)
+
+	var result []common.FunctionRuntimeStats
+	days := make(map[string][]*common.FunctionRuntimeStats)
+
+	for _, trace := range traces {
+		for i := range *trace {
+			stats := &(*trace)[i]
+
+			if _, ok := days[stats.HashFunction]; !ok {
+				result = append(result, common.FunctionRuntimeStats{
+					HashOwner:    stats.HashOwner,
+					HashApp:      stats.HashApp,
+					HashFunction: stats.HashFunction,
+				})
+			}
+			days[stats.HashFunction] = append(days[stats.HashFunction], stats)
+		}
+	}
+
+	for i := range result {
+		var fields [][]*float64
+		var counts []float64
+
+		for _, stats := range days[result[i].HashFunction] {
+			fields = append(fields, runtimeStatsFields(stats))
+			counts = append(counts, stats.Count)
+			result[i].Count += stats.Count
+		}
+
+		mergeFields(runtimeStatsFields(&result[i]), fields, counts, policy)
+	}
+
+	return &result
+}
+
+// mergeMemoryTraces merges the memory statistics of each function across days. Count is always summed.
+func mergeMemoryTraces(traces []*[]common.FunctionMemoryStats, policy string) *[]common.FunctionMemoryStats {
+	if len(traces) == 1 {
+		return traces[0]
+	}
+	policy = defaultMergePolicy(policy)
+
+	var result []common.FunctionMemoryStats
+	days := make(map[string][]*common.FunctionMemoryStats)
+
+	for _, trace := range traces {
+		for i := range *trace {
+			stats := &(*trace)[i]
+
+			if _, ok := days[stats.HashFunction]; !ok {
+				result = append(result, common.FunctionMemoryStats{
+					HashOwner:    stats.HashOwner,
+					HashApp:      stats.HashApp,
+					HashFunction: stats.HashFunction,
+				})
+			}
+			days[stats.HashFunction] = append(days[stats.HashFunction], stats)
+		}
+	}
+
+	for i := range result {
+		var fields [][]*float64
+		var counts []float64
+
+		for _, stats := range days[result[i].HashFunction] {
+			fields = append(fields, memoryStatsFields(stats))
+			counts = append(counts, stats.Count)
+			result[i].Count += stats.Count
+		}
+
+		mergeFields(memoryStatsFields(&result[i]), fields, counts, policy)
+	}
+
+	return &result
+}
@@ -0,0 +1,160 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package trace
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+func TestResolveTraceFiles(t *testing.T) {
+	files := resolveTraceFiles("test_data", []string{"multi_day/invocations_per_function_md.anon.d*"})
+
+	if len(files) != 2 ||
+		files[0] != filepath.Join("test_data", "multi_day", "invocations_per_function_md.anon.d01.csv") ||
+		files[1] != filepath.Join("test_data", "multi_day", "invocations_per_function_md.anon.d02.csv") {
+
+		t.Errorf("Unexpected trace files %v.", files)
+	}
+
+	files = resolveTraceFiles("test_data", []string{"b.csv", "/tmp/a.csv"})
+	if len(files) != 2 || files[0] != filepath.Join("test_data", "b.csv") || files[1] != "/tmp/a.csv" {
+		t.Errorf("Unexpected trace files %v.", files)
+	}
+}
+
+func TestParseMultiDayInvocationTrace(t *testing.T) {
+	files := resolveTraceFiles("test_data", []string{"multi_day/invocations_per_function_md.anon.d*"})
+
+	tests := []struct {
+		testName    string
+		startMinute int
+		duration    int
+		functions   []string
+		invocations [][]int
+	}{
+		{
+			testName:    "both_days",
+			startMinute: 0,
+			duration:    6,
+			functions:   []string{"f1", "f2", "f3"},
+			invocations: [][]int{{1, 2, 3, 7, 8, 9}, {4, 5, 6, 0, 0, 0}, {0, 0, 0, 1, 1, 1}},
+		},
+		{
+			testName:    "across_midnight",
+			startMinute: 2,
+			duration:    3,
+			functions:   []string{"f1", "f2", "f3"},
+			invocations: [][]int{{3, 7, 8}, {6, 0, 0}, {0, 1, 1}},
+		},
+		{
+			testName:    "second_day_beyond_trace",
+			startMinute: 4,
+			duration:    4,
+			functions:   []string{"f1", "f3"},
+			invocations: [][]int{{8, 9, 0, 0}, {1, 1, 0, 0}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			trace := *parseInvocationTraces(files, test.startMinute, test.duration)
+
+			if len(trace) != len(test.functions) {
+				t.Fatalf("Expected %d functions, got %d.", len(test.functions), len(trace))
+			}
+
+			for i, function := range trace {
+				if function.HashFunction != test.functions[i] || len(function.Invocations) != test.duration {
+					t.Fatalf("Unexpected function %s.", function.HashFunction)
+				}
+
+				for j := 0; j < test.duration; j++ {
+					if function.Invocations[j] != test.invocations[i][j] {
+						t.Errorf("Unexpected number of invocations of %s in minute %d - got %d, expected %d.",
+							function.HashFunction, j, function.Invocations[j], test.invocations[i][j])
+					}
+				}
+			}
+
+			// streamed traces read the same invocations in windows, which may span both days
+			index := *indexInvocationTraces(files, test.startMinute, test.duration)
+			for i, function := range index {
+				if function.Invocations != nil || function.TimeUnits() != test.duration {
+					t.Fatalf("Unexpected index of function %s.", function.HashFunction)
+				}
+
+				split := test.duration / 2
+				invocations := append(function.InvocationWindow(0, split), function.InvocationWindow(split, test.duration)...)
+				if !reflect.DeepEqual(invocations, test.invocations[i]) {
+					t.Errorf("Unexpected invocations of %s read in windows - got %v, expected %v.",
+						function.HashFunction, invocations, test.invocations[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMergeRuntimeTraces(t *testing.T) {
+	traces := []*[]common.FunctionRuntimeStats{
+		parseRuntimeTrace("test_data/multi_day/function_durations_percentiles.anon.d01.csv"),
+		parseRuntimeTrace("test_data/multi_day/function_durations_percentiles.anon.d02.csv"),
+	}
+
+	tests := []struct {
+		policy  string
+		average float64
+		maximum float64
+		p50     float64
+	}{
+		{policy: StatsMergeFirst, average: 10, maximum: 30, p50: 10},
+		{policy: StatsMergeLast, average: 20, maximum: 40, p50: 20},
+		{policy: StatsMergeMax, average: 20, maximum: 40, p50: 20},
+		{policy: StatsMergeAverage, average: 17.5, maximum: 37.5, p50: 17.5},
+		{policy: "", average: 17.5, maximum: 37.5, p50: 17.5},
+	}
+
+	for _, test := range tests {
+		merged := *mergeRuntimeTraces(traces, test.policy)
+
+		if len(merged) != 2 || merged[0].HashFunction != "f1" || merged[1].HashFunction != "f3" {
+			t.Fatalf("Unexpected functions after merging with policy '%s'.", test.policy)
+		}
+
+		f1 := merged[0]
+		if !floatEqual(f1.Count, 400) || !floatEqual(f1.Average, test.average) ||
+			!floatEqual(f1.Maximum, test.maximum) || !floatEqual(f1.Percentile50, test.p50) {
+
+			t.Errorf("Unexpected runtime statistics after merging with policy '%s'.", test.policy)
+		}
+
+		if !floatEqual(merged[1].Count, 10) || !floatEqual(merged[1].Average, 5) {
+			t.Errorf("Function present on a single day should not change with policy '%s'.", test.policy)
+		}
+	}
+}
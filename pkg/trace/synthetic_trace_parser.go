@@ -0,0 +1,122 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package trace
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+	"github.com/vhive-serverless/loader/pkg/generator"
+)
+
+// SyntheticParser creates a trace without reading any files, where all functions have the same invocation rate,
+// runtime and memory as set in the configuration
+type SyntheticParser struct {
+	functionCount        int
+	invocationsPerMinute int
+	runtimeMs            int
+	memoryMiB            int
+
+	duration              int
+	granularity           common.TraceGranularity
+	functionNameGenerator *rand.Rand
+}
+
+func NewSyntheticParser(cfg *config.LoaderConfiguration, totalDuration int, granularity common.TraceGranularity) *SyntheticParser {
+	return &SyntheticParser{
+		functionCount:        cfg.SyntheticFunctionCount,
+		invocationsPerMinute: cfg.SyntheticInvocationsPerMinute,
+		runtimeMs:            cfg.SyntheticRuntimeMs,
+		memoryMiB:            cfg.SyntheticMemoryMiB,
+
+		duration:              totalDuration,
+		granularity:           granularity,
+		functionNameGenerator: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+func (p *SyntheticParser) invocations() []int {
+	duration := common.MaxOf(p.duration, 1)
+	result := make([]int, duration)
+
+	for i := 0; i < duration; i++ {
+		if p.granularity == common.SecondGranularity {
+			// spread invocations of each minute evenly across its seconds
+			second := i % 60
+			result[i] = (second+1)*p.invocationsPerMinute/60 - second*p.invocationsPerMinute/60
+		} else {
+			result[i] = p.invocationsPerMinute
+		}
+	}
+
+	return result
+}
+
+func (p *SyntheticParser) Parse() []*common.Function {
+	if p.functionCount <= 0 || p.invocationsPerMinute < 0 || p.runtimeMs <= 0 || p.memoryMiB <= 0 {
+		log.Fatal("Synthetic trace requires positive SyntheticFunctionCount, SyntheticRuntimeMs and SyntheticMemoryMiB, and non-negative SyntheticInvocationsPerMinute.")
+	}
+
+	log.Infof("Creating synthetic trace with %d functions (%d invocations per minute, %d ms, %d MiB)",
+		p.functionCount, p.invocationsPerMinute, p.runtimeMs, p.memoryMiB)
+
+	var result []*common.Function
+
+	for i := 0; i < p.functionCount; i++ {
+		hash := fmt.Sprintf("synthetic-%d", i)
+
+		invocationStats := &common.FunctionInvocationStats{
+			HashOwner:    hash,
+			HashApp:      hash,
+			HashFunction: hash,
+			Trigger:      "http",
+			Invocations:  p.invocations(),
+		}
+
+		runtimeStats := createRuntimeStats([]float64{float64(p.runtimeMs)})
+		runtimeStats.HashOwner, runtimeStats.HashApp, runtimeStats.HashFunction = hash, hash, hash
+
+		memoryStats := createMemoryStats([]float64{float64(p.memoryMiB)})
+		memoryStats.HashOwner, memoryStats.HashApp, memoryStats.HashFunction = hash, hash, hash
+
+		function := &common.Function{
+			Name: fmt.Sprintf("%s-%d-%d", common.FunctionNamePrefix, i, p.functionNameGenerator.Uint64()),
+
+			InvocationStats: invocationStats,
+			RuntimeStats:    runtimeStats,
+			MemoryStats:     memoryStats,
+
+			ColdStartBusyLoopMs: generator.ComputeBusyLoopPeriod(p.memoryMiB),
+		}
+
+		result = append(result, function)
+	}
+
+	return result
+}
@@ -0,0 +1,95 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package trace
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+const tenantNamePrefix = "tenant"
+
+// AssignTenants assigns each function to the tenant its owner is listed in. Owners that are not listed in any of
+// the configured tenants get a tenant of their own with the default quota. Returns the tenants that have at least one
+// function, in the order of the configuration followed by the generated ones.
+func AssignTenants(functions []*common.Function, tenants []config.TenantConfiguration, defaultQuota config.TenantQuota) []config.TenantConfiguration {
+	tenantByOwner := make(map[string]int)
+	for i, tenant := range tenants {
+		if tenant.Name == "" {
+			log.Fatalf("Tenant %d has no name.", i)
+		}
+
+		for _, owner := range tenant.Owners {
+			if other, ok := tenantByOwner[owner]; ok && other != i {
+				log.Fatalf("Owner %s is assigned to both tenant %s and %s.", owner, tenants[other].Name, tenant.Name)
+			}
+
+			tenantByOwner[owner] = i
+		}
+	}
+
+	result := append([]config.TenantConfiguration{}, tenants...)
+	used := make([]bool, len(tenants))
+
+	for _, function := range functions {
+		owner := ""
+		if function.InvocationStats != nil {
+			owner = function.InvocationStats.HashOwner
+		}
+
+		index, ok := tenantByOwner[owner]
+		if !ok {
+			index = len(result)
+			tenantByOwner[owner] = index
+
+			result = append(result, config.TenantConfiguration{
+				Name:   fmt.Sprintf("%s-%d", tenantNamePrefix, index),
+				Owners: []string{owner},
+				Quota:  defaultQuota,
+			})
+			used = append(used, false)
+		}
+
+		function.Tenant = result[index].Name
+		used[index] = true
+	}
+
+	var assigned []config.TenantConfiguration
+	for i, tenant := range result {
+		if !used[i] {
+			log.Warnf("Tenant %s has no functions in the trace.", tenant.Name)
+			continue
+		}
+
+		assigned = append(assigned, tenant)
+	}
+
+	log.Infof("Assigned %d functions to %d tenants", len(functions), len(assigned))
+
+	return assigned
+}
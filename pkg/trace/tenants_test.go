@@ -0,0 +1,76 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package trace
+
+import (
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+func TestAssignTenants(t *testing.T) {
+	functionOf := func(name, owner string) *common.Function {
+		return &common.Function{Name: name, InvocationStats: &common.FunctionInvocationStats{HashOwner: owner}}
+	}
+
+	functions := []*common.Function{
+		functionOf("f0", "owner-a"),
+		functionOf("f1", "owner-b"),
+		functionOf("f2", "owner-c"),
+		functionOf("f3", "owner-a"),
+		functionOf("f4", "owner-d"),
+	}
+
+	groupQuota := config.TenantQuota{CPUMilli: 4000, MemoryMiB: 8192}
+	defaultQuota := config.TenantQuota{Pods: 10}
+
+	tenants := AssignTenants(functions, []config.TenantConfiguration{
+		{Name: "group", Owners: []string{"owner-a", "owner-c"}, Quota: groupQuota},
+		{Name: "unused", Owners: []string{"owner-x"}},
+	}, defaultQuota)
+
+	if len(tenants) != 3 {
+		t.Fatalf("Expected 3 tenants, got %d.", len(tenants))
+	}
+	if tenants[0].Name != "group" || tenants[0].Quota != groupQuota {
+		t.Errorf("Unexpected configured tenant %+v.", tenants[0])
+	}
+	for _, tenant := range tenants[1:] {
+		if tenant.Name == "unused" || tenant.Quota != defaultQuota || len(tenant.Owners) != 1 {
+			t.Errorf("Unexpected generated tenant %+v.", tenant)
+		}
+	}
+
+	expected := []string{"group", tenants[1].Name, "group", "group", tenants[2].Name}
+	for i, function := range functions {
+		if function.Tenant != expected[i] {
+			t.Errorf("Function %s assigned to tenant %s, expected %s.", function.Name, function.Tenant, expected[i])
+		}
+	}
+	if tenants[1].Name == tenants[2].Name {
+		t.Error("Owners without a configured tenant should get distinct tenants.")
+	}
+}
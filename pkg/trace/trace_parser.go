@@ -0,0 +1,122 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package trace
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+// TraceParser reads the functions of a trace together with their invocation, runtime and memory statistics. Parsers
+// of traces containing the arrival time of each invocation also set the function specification, in which case it is
+// not generated by the driver.
+type TraceParser interface {
+	Parse() []*common.Function
+}
+
+// TraceParserFactory creates a parser for a trace of the given duration, expressed in time units of the granularity
+type TraceParserFactory func(cfg *config.LoaderConfiguration, duration int, granularity common.TraceGranularity) TraceParser
+
+const DefaultTraceFormat = "azure_2019"
+
+var (
+	traceParsersMutex sync.RWMutex
+	traceParsers      = map[string]TraceParserFactory{
+		"azure_2019": func(cfg *config.LoaderConfiguration, duration int, _ common.TraceGranularity) TraceParser {
+			return NewAzureMultiDayParser(cfg.TracePath, duration, cfg)
+		},
+		"azure_2021": func(cfg *config.LoaderConfiguration, duration int, _ common.TraceGranularity) TraceParser {
+			return NewAzure2021Parser(cfg.TracePath, duration)
+		},
+		"huawei": func(cfg *config.LoaderConfiguration, duration int, granularity common.TraceGranularity) TraceParser {
+			return NewHuaweiParser(cfg.TracePath, duration, granularity)
+		},
+		"alibaba": func(cfg *config.LoaderConfiguration, duration int, granularity common.TraceGranularity) TraceParser {
+			return NewAlibabaParser(cfg.TracePath, duration, granularity)
+		},
+		"csv": func(cfg *config.LoaderConfiguration, duration int, _ common.TraceGranularity) TraceParser {
+			return NewGenericCSVParser(cfg.TracePath, duration, cfg.TraceColumnMapping)
+		},
+		"synthetic": func(cfg *config.LoaderConfiguration, duration int, granularity common.TraceGranularity) TraceParser {
+			return NewSyntheticParser(cfg, duration, granularity)
+		},
+	}
+)
+
+// RegisterTraceParser makes a trace format available to all tools using NewTraceParser or ParseTrace
+func RegisterTraceParser(format string, factory TraceParserFactory) {
+	traceParsersMutex.Lock()
+	defer traceParsersMutex.Unlock()
+
+	traceParsers[strings.ToLower(format)] = factory
+}
+
+// SupportedTraceFormats returns the sorted names of all registered trace formats
+func SupportedTraceFormats() []string {
+	traceParsersMutex.RLock()
+	defer traceParsersMutex.RUnlock()
+
+	var result []string
+	for format := range traceParsers {
+		result = append(result, format)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// NewTraceParser creates a parser for the trace format selected by TraceFormat, which defaults to azure_2019
+func NewTraceParser(cfg *config.LoaderConfiguration, duration int, granularity common.TraceGranularity) TraceParser {
+	format := strings.ToLower(cfg.TraceFormat)
+	if format == "" {
+		format = DefaultTraceFormat
+	}
+
+	traceParsersMutex.RLock()
+	factory, ok := traceParsers[format]
+	traceParsersMutex.RUnlock()
+
+	if !ok {
+		log.Fatalf("Unsupported trace format '%s'. Supported formats are %v.", cfg.TraceFormat, SupportedTraceFormats())
+	}
+
+	return factory(cfg, duration, granularity)
+}
+
+// ParseTrace parses the trace selected by the configuration and passes the functions through the chain of enrichers
+func ParseTrace(cfg *config.LoaderConfiguration, duration int, granularity common.TraceGranularity, enrichers ...MetadataEnricher) []*common.Function {
+	functions := NewTraceParser(cfg, duration, granularity).Parse()
+
+	for _, enricher := range enrichers {
+		enricher.Enrich(functions)
+	}
+
+	return functions
+}
@@ -0,0 +1,156 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package trace
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
+)
+
+type fakeTraceParser struct{}
+
+func (p *fakeTraceParser) Parse() []*common.Function {
+	return []*common.Function{{Name: "fake", InvocationStats: &common.FunctionInvocationStats{HashFunction: "fake"}}}
+}
+
+type fakeEnricher struct {
+	image string
+}
+
+func (e *fakeEnricher) Enrich(functions []*common.Function) {
+	for _, function := range functions {
+		function.DirigentMetadata = &common.DirigentMetadata{Image: e.image}
+	}
+}
+
+func TestTraceParserSelection(t *testing.T) {
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{format: "", expected: "*trace.AzureTraceParser"},
+		{format: "azure_2019", expected: "*trace.AzureTraceParser"},
+		{format: "Azure_2021", expected: "*trace.Azure2021TraceParser"},
+		{format: "huawei", expected: "*trace.HuaweiTraceParser"},
+		{format: "alibaba", expected: "*trace.AlibabaTraceParser"},
+		{format: "csv", expected: "*trace.GenericCSVParser"},
+		{format: "synthetic", expected: "*trace.SyntheticParser"},
+	}
+
+	for _, test := range tests {
+		parser := NewTraceParser(&config.LoaderConfiguration{TraceFormat: test.format}, 1, common.MinuteGranularity)
+
+		if parserType := fmt.Sprintf("%T", parser); parserType != test.expected {
+			t.Errorf("Unexpected parser for trace format '%s' - got %s, expected %s.", test.format, parserType, test.expected)
+		}
+	}
+}
+
+func TestRegisterTraceParserWithEnrichers(t *testing.T) {
+	RegisterTraceParser("fake", func(*config.LoaderConfiguration, int, common.TraceGranularity) TraceParser {
+		return &fakeTraceParser{}
+	})
+
+	functions := ParseTrace(&config.LoaderConfiguration{TraceFormat: "fake"}, 1, common.MinuteGranularity,
+		&fakeEnricher{image: "first"}, &fakeEnricher{image: "second"})
+
+	if len(functions) != 1 || functions[0].Name != "fake" {
+		t.Fatal("Registered trace parser has not been used.")
+	}
+
+	if functions[0].DirigentMetadata == nil || functions[0].DirigentMetadata.Image != "second" {
+		t.Error("Enrichers have not been applied in order.")
+	}
+}
+
+func TestGenericCSVParser(t *testing.T) {
+	functions := NewGenericCSVParser("test_data/generic", 3, map[string]string{
+		ColumnHashApp:      "app",
+		ColumnHashFunction: "fn",
+		ColumnTime:         "minute",
+		ColumnInvocations:  "count",
+		ColumnRuntime:      "avg_ms",
+		ColumnMemory:       "mem_mb",
+	}).Parse()
+
+	if len(functions) != 2 {
+		t.Fatalf("Expected 2 functions, got %d.", len(functions))
+	}
+
+	f1, f2 := functions[0], functions[1]
+	if f1.InvocationStats.HashApp != "a1" || f1.InvocationStats.HashFunction != "f1" || f2.InvocationStats.HashFunction != "f2" {
+		t.Error("Unexpected function identifiers.")
+	}
+
+	expected := [][]int{{3, 0, 1}, {0, 2, 0}}
+	for i, function := range functions {
+		for j := 0; j < len(expected[i]); j++ {
+			if function.InvocationStats.Invocations[j] != expected[i][j] {
+				t.Errorf("Unexpected number of invocations of function %d in minute %d.", i, j)
+			}
+		}
+	}
+
+	if !floatEqual(f1.RuntimeStats.Average, 200) || !floatEqual(f1.RuntimeStats.Count, 2) ||
+		!floatEqual(f1.MemoryStats.Percentile100, 256) || !floatEqual(f2.RuntimeStats.Maximum, 50) {
+
+		t.Error("Unexpected runtime or memory statistics.")
+	}
+}
+
+func TestSyntheticParser(t *testing.T) {
+	cfg := &config.LoaderConfiguration{
+		SyntheticFunctionCount:        3,
+		SyntheticInvocationsPerMinute: 90,
+		SyntheticRuntimeMs:            250,
+		SyntheticMemoryMiB:            512,
+	}
+
+	functions := NewSyntheticParser(cfg, 2, common.MinuteGranularity).Parse()
+	if len(functions) != 3 {
+		t.Fatalf("Expected 3 functions, got %d.", len(functions))
+	}
+
+	for _, function := range functions {
+		if len(function.InvocationStats.Invocations) != 2 || function.InvocationStats.Invocations[0] != 90 ||
+			!floatEqual(function.RuntimeStats.Percentile50, 250) || !floatEqual(function.MemoryStats.Percentile100, 512) {
+
+			t.Error("Unexpected synthetic function.")
+		}
+	}
+
+	functions = NewSyntheticParser(cfg, 120, common.SecondGranularity).Parse()
+
+	perMinute := 0
+	for i := 0; i < 60; i++ {
+		perMinute += functions[0].InvocationStats.Invocations[i]
+	}
+	if perMinute != 90 {
+		t.Errorf("Expected 90 invocations in the first minute, got %d.", perMinute)
+	}
+}
@@ -51,9 +51,12 @@ func ApplyResourceLimits(functions []*common.Function, CPULimit string) {
 			cpuShare = ConvertMemoryToCpu(memoryPct100)
 		}
 
-		functions[i].CPURequestsMilli = cpuShare / common.OvercommitmentRatio
+		// CPU requests taken from the CPU usage in the trace are kept
+		if functions[i].CPURequestsMilli == 0 {
+			functions[i].CPURequestsMilli = cpuShare / common.OvercommitmentRatio
+		}
 		functions[i].MemoryRequestsMiB = memoryPct100 / common.OvercommitmentRatio
-		functions[i].CPULimitsMilli = cpuShare
+		functions[i].CPULimitsMilli = max(cpuShare, functions[i].CPURequestsMilli)
 	}
 }
 
@@ -79,7 +82,7 @@ func ConvertMemoryToCpu(memoryRequest int) int {
 }
 
 func profileConcurrency(function *common.Function) float64 {
-	IPM := function.InvocationStats.Invocations[0]
+	IPM := function.InvocationStats.InvocationWindow(0, 1)[0]
 
 	// Arrival rate - unit 1 s
 	rps := float64(IPM) / 60.0
@@ -108,3 +108,16 @@ func TestStaticTraceProfiling(t *testing.T) {
 		})
 	}
 }
+
+func TestResourceLimitsWithTraceCPU(t *testing.T) {
+	f := &common.Function{
+		MemoryStats:      &common.FunctionMemoryStats{Percentile100: 256},
+		CPURequestsMilli: 1500,
+	}
+
+	ApplyResourceLimits([]*common.Function{f}, "1vCPU")
+
+	if f.CPURequestsMilli != 1500 || f.CPULimitsMilli != 1500 {
+		t.Errorf("Expected the CPU request of the trace to be kept, got %d and a limit of %d.", f.CPURequestsMilli, f.CPULimitsMilli)
+	}
+}
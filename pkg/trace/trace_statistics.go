@@ -0,0 +1,103 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package trace
+
+import (
+	"math"
+	"sort"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+// createRuntimeStats summarizes execution time samples (in ms) the same way as the Azure durations.csv does
+func createRuntimeStats(samples []float64) *common.FunctionRuntimeStats {
+	stats := &common.FunctionRuntimeStats{
+		Count: float64(len(samples)),
+	}
+
+	if len(samples) == 0 {
+		return stats
+	}
+
+	sorted := append([]float64{}, samples...)
+	sort.Float64s(sorted)
+
+	stats.Average = average(sorted)
+	stats.Minimum = sorted[0]
+	stats.Maximum = sorted[len(sorted)-1]
+
+	stats.Percentile0 = sorted[0]
+	stats.Percentile1 = percentile(sorted, 1)
+	stats.Percentile25 = percentile(sorted, 25)
+	stats.Percentile50 = percentile(sorted, 50)
+	stats.Percentile75 = percentile(sorted, 75)
+	stats.Percentile99 = percentile(sorted, 99)
+	stats.Percentile100 = sorted[len(sorted)-1]
+
+	return stats
+}
+
+// createMemoryStats summarizes memory samples (in MiB) the same way as the Azure memory.csv does
+func createMemoryStats(samples []float64) *common.FunctionMemoryStats {
+	stats := &common.FunctionMemoryStats{
+		Count: float64(len(samples)),
+	}
+
+	if len(samples) == 0 {
+		return stats
+	}
+
+	sorted := append([]float64{}, samples...)
+	sort.Float64s(sorted)
+
+	stats.Average = average(sorted)
+
+	stats.Percentile1 = percentile(sorted, 1)
+	stats.Percentile5 = percentile(sorted, 5)
+	stats.Percentile25 = percentile(sorted, 25)
+	stats.Percentile50 = percentile(sorted, 50)
+	stats.Percentile75 = percentile(sorted, 75)
+	stats.Percentile95 = percentile(sorted, 95)
+	stats.Percentile99 = percentile(sorted, 99)
+	stats.Percentile100 = sorted[len(sorted)-1]
+
+	return stats
+}
+
+func average(samples []float64) float64 {
+	sum := 0.0
+	for _, sample := range samples {
+		sum += sample
+	}
+
+	return sum / float64(len(samples))
+}
+
+// percentile returns the nearest-rank percentile of a sorted array
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+
+	return sorted[common.MinOf(common.MaxOf(rank, 0), len(sorted)-1)]
+}
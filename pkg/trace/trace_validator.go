@@ -0,0 +1,460 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package trace
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+)
+
+type ValidationSeverity string
+
+const (
+	SeverityError   ValidationSeverity = "error"
+	SeverityWarning ValidationSeverity = "warning"
+)
+
+// checks reported by the trace validator
+const (
+	CheckMissingFile       = "missing_file"
+	CheckMalformedFile     = "malformed_file"
+	CheckMissingColumn     = "missing_column"
+	CheckMalformedRow      = "malformed_row"
+	CheckInvalidValue      = "invalid_value"
+	CheckNegativeValue     = "negative_value"
+	CheckZeroCount         = "zero_count"
+	CheckNonMonotone       = "non_monotone_percentiles"
+	CheckDuplicateFunction = "duplicate_function"
+	CheckMismatchedHash    = "mismatched_hash"
+	CheckMinutesBeyond     = "minutes_beyond_trace"
+)
+
+type ValidationIssue struct {
+	Severity     ValidationSeverity `json:"severity"`
+	Check        string             `json:"check"`
+	File         string             `json:"file"`
+	Line         int                `json:"line,omitempty"`
+	HashFunction string             `json:"hashFunction,omitempty"`
+	Message      string             `json:"message"`
+}
+
+// ValidationReport is the outcome of validating a trace in the Azure format together with its Dirigent metadata
+type ValidationReport struct {
+	TracePath string `json:"tracePath"`
+	Functions int    `json:"functions"`
+	Minutes   int    `json:"minutes"`
+	Errors    int    `json:"errors"`
+	Warnings  int    `json:"warnings"`
+
+	Issues []ValidationIssue `json:"issues"`
+}
+
+type statsFileSpecification struct {
+	name        string
+	countColumn string
+	// numeric columns, where percentiles are listed in increasing order
+	numericColumns    []string
+	percentileColumns []string
+}
+
+var (
+	hashColumns = []string{"HashOwner", "HashApp", "HashFunction"}
+
+	runtimeFileSpecification = statsFileSpecification{
+		name:              "durations.csv",
+		countColumn:       "Count",
+		numericColumns:    []string{"Average", "Count", "Minimum", "Maximum"},
+		percentileColumns: []string{"percentile_Average_0", "percentile_Average_1", "percentile_Average_25", "percentile_Average_50", "percentile_Average_75", "percentile_Average_99", "percentile_Average_100"},
+	}
+
+	memoryFileSpecification = statsFileSpecification{
+		name:              "memory.csv",
+		countColumn:       "SampleCount",
+		numericColumns:    []string{"SampleCount", "AverageAllocatedMb"},
+		percentileColumns: []string{"AverageAllocatedMb_pct1", "AverageAllocatedMb_pct5", "AverageAllocatedMb_pct25", "AverageAllocatedMb_pct50", "AverageAllocatedMb_pct75", "AverageAllocatedMb_pct95", "AverageAllocatedMb_pct99", "AverageAllocatedMb_pct100"},
+	}
+)
+
+// ValidateTrace checks the invocation, duration, memory and Dirigent files in the trace directory for consistency.
+// Unlike the parsers, it does not stop at the first problem, but reports all of them. The duration of the experiment
+// in minutes is used to report minutes that are not covered by the trace, and is ignored if not positive.
+func ValidateTrace(tracePath string, duration int) *ValidationReport {
+	report := &ValidationReport{
+		TracePath: tracePath,
+		Issues:    []ValidationIssue{},
+	}
+
+	invocationHashes := report.validateInvocationFile(filepath.Join(tracePath, "invocations.csv"), duration)
+	runtimeHashes := report.validateStatsFile(filepath.Join(tracePath, runtimeFileSpecification.name), runtimeFileSpecification)
+	memoryHashes := report.validateStatsFile(filepath.Join(tracePath, memoryFileSpecification.name), memoryFileSpecification)
+
+	if invocationHashes != nil {
+		report.validateHashes(invocationHashes, runtimeHashes, runtimeFileSpecification.name, SeverityError)
+		report.validateHashes(invocationHashes, memoryHashes, memoryFileSpecification.name, SeverityError)
+	}
+
+	dirigentPath := filepath.Join(tracePath, "dirigent.json")
+	if _, err := os.Stat(dirigentPath); err == nil {
+		dirigentHashes := report.validateDirigentFile(dirigentPath)
+		if invocationHashes != nil {
+			report.validateHashes(invocationHashes, dirigentHashes, "dirigent.json", SeverityWarning)
+		}
+	}
+
+	return report
+}
+
+func (r *ValidationReport) addIssue(severity ValidationSeverity, check string, file string, line int, hashFunction string, format string, args ...interface{}) {
+	r.Issues = append(r.Issues, ValidationIssue{
+		Severity:     severity,
+		Check:        check,
+		File:         filepath.Base(file),
+		Line:         line,
+		HashFunction: hashFunction,
+		Message:      fmt.Sprintf(format, args...),
+	})
+
+	if severity == SeverityError {
+		r.Errors++
+	} else {
+		r.Warnings++
+	}
+}
+
+func (r *ValidationReport) HasErrors() bool {
+	return r.Errors > 0
+}
+
+// readValidatedCSV reads the whole file, reporting it as missing or malformed instead of failing
+func (r *ValidationReport) readValidatedCSV(path string) [][]string {
+	file, err := os.Open(path)
+	if err != nil {
+		r.addIssue(SeverityError, CheckMissingFile, path, 0, "", "Failed to open the file - %v", err)
+		return nil
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+
+	var records [][]string
+	for {
+		record, err := reader.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			r.addIssue(SeverityError, CheckMalformedFile, path, len(records)+1, "", "Failed to read the file - %v", err)
+			return nil
+		}
+
+		records = append(records, record)
+	}
+
+	if len(records) == 0 {
+		r.addIssue(SeverityError, CheckMalformedFile, path, 0, "", "The file is empty.")
+		return nil
+	}
+
+	return records
+}
+
+// columnIndices returns the index of each required column in the header, reporting the missing ones
+func (r *ValidationReport) columnIndices(path string, header []string, columns []string) (map[string]int, bool) {
+	indices := make(map[string]int)
+	for i, column := range header {
+		indices[strings.TrimSpace(column)] = i
+	}
+
+	ok := true
+	for _, column := range columns {
+		if _, found := indices[column]; !found {
+			r.addIssue(SeverityError, CheckMissingColumn, path, 1, "", "Column %s is missing.", column)
+			ok = false
+		}
+	}
+
+	return indices, ok
+}
+
+// validateInvocationFile returns the line of each function by its HashFunction, or nil if the file cannot be checked
+func (r *ValidationReport) validateInvocationFile(path string, duration int) map[string]int {
+	records := r.readValidatedCSV(path)
+	if records == nil {
+		return nil
+	}
+
+	header := records[0]
+	indices, ok := r.columnIndices(path, header, hashColumns)
+	if !ok {
+		return nil
+	}
+
+	firstMinuteColumn := len(hashColumns)
+	if index, found := indices["Trigger"]; found {
+		firstMinuteColumn = index + 1
+	} else {
+		r.addIssue(SeverityWarning, CheckMissingColumn, path, 1, "", "Column Trigger is missing.")
+	}
+
+	r.Minutes = len(header) - firstMinuteColumn
+	for i := firstMinuteColumn; i < len(header); i++ {
+		if minute, err := strconv.Atoi(strings.TrimSpace(header[i])); err != nil || minute != i-firstMinuteColumn+1 {
+			r.addIssue(SeverityError, CheckMissingColumn, path, 1, "", "Expected column of minute %d, found %q.", i-firstMinuteColumn+1, header[i])
+			break
+		}
+	}
+
+	if r.Minutes == 0 {
+		r.addIssue(SeverityError, CheckMissingColumn, path, 1, "", "The trace does not contain any minute columns.")
+	}
+	if duration > r.Minutes {
+		r.addIssue(SeverityWarning, CheckMinutesBeyond, path, 1, "", "The experiment lasts %d minutes, but the trace contains only %d. Minutes beyond the trace have no invocations.", duration, r.Minutes)
+	}
+
+	result := make(map[string]int)
+	functions := make(map[string]int)
+
+	for row, record := range records[1:] {
+		line := row + 2
+		if len(record) != len(header) {
+			r.addIssue(SeverityError, CheckMalformedRow, path, line, "", "Expected %d fields, found %d.", len(header), len(record))
+			continue
+		}
+
+		hashFunction := record[indices["HashFunction"]]
+		key := record[indices["HashOwner"]] + record[indices["HashApp"]] + hashFunction
+
+		if previous, found := functions[key]; found {
+			r.addIssue(SeverityError, CheckDuplicateFunction, path, line, hashFunction, "Function is already defined on line %d.", previous)
+			continue
+		}
+		functions[key] = line
+
+		if previous, found := result[hashFunction]; found {
+			r.addIssue(SeverityWarning, CheckDuplicateFunction, path, line, hashFunction, "HashFunction is shared with the function on line %d, so both get the same duration and memory statistics.", previous)
+		} else {
+			result[hashFunction] = line
+		}
+
+		total := 0
+		for i := firstMinuteColumn; i < len(record); i++ {
+			count, err := strconv.Atoi(strings.TrimSpace(record[i]))
+			if err != nil {
+				r.addIssue(SeverityError, CheckInvalidValue, path, line, hashFunction, "Invocation count %q of minute %s is not an integer.", record[i], header[i])
+				continue
+			}
+			if count < 0 {
+				r.addIssue(SeverityError, CheckNegativeValue, path, line, hashFunction, "Invocation count of minute %s is negative (%d).", header[i], count)
+				continue
+			}
+
+			total += count
+		}
+
+		if total == 0 {
+			r.addIssue(SeverityWarning, CheckZeroCount, path, line, hashFunction, "Function has no invocations.")
+		}
+	}
+
+	r.Functions = len(functions)
+
+	return result
+}
+
+// validateStatsFile returns the line of each function by its HashFunction, or nil if the file cannot be checked
+func (r *ValidationReport) validateStatsFile(path string, specification statsFileSpecification) map[string]int {
+	records := r.readValidatedCSV(path)
+	if records == nil {
+		return nil
+	}
+
+	header := records[0]
+	numericColumns := append(append([]string{}, specification.numericColumns...), specification.percentileColumns...)
+
+	indices, ok := r.columnIndices(path, header, append(append([]string{}, hashColumns...), numericColumns...))
+	if !ok {
+		return nil
+	}
+
+	result := make(map[string]int)
+
+	for row, record := range records[1:] {
+		line := row + 2
+		if len(record) != len(header) {
+			r.addIssue(SeverityError, CheckMalformedRow, path, line, "", "Expected %d fields, found %d.", len(header), len(record))
+			continue
+		}
+
+		hashFunction := record[indices["HashFunction"]]
+		if previous, found := result[hashFunction]; found {
+			r.addIssue(SeverityError, CheckDuplicateFunction, path, line, hashFunction, "Function is already defined on line %d.", previous)
+			continue
+		}
+		result[hashFunction] = line
+
+		values := make(map[string]float64)
+		valid := true
+		for _, column := range numericColumns {
+			value, err := strconv.ParseFloat(strings.TrimSpace(record[indices[column]]), 64)
+			if err != nil {
+				r.addIssue(SeverityError, CheckInvalidValue, path, line, hashFunction, "Value %q of column %s is not a number.", record[indices[column]], column)
+				valid = false
+				continue
+			}
+			if value < 0 {
+				r.addIssue(SeverityError, CheckNegativeValue, path, line, hashFunction, "Value of column %s is negative (%v).", column, value)
+			}
+
+			values[column] = value
+		}
+
+		if !valid {
+			continue
+		}
+
+		if values[specification.countColumn] <= 0 {
+			r.addIssue(SeverityError, CheckZeroCount, path, line, hashFunction, "%s is not positive, so no specification can be generated for the function.", specification.countColumn)
+		}
+
+		if minimum, found := values["Minimum"]; found && minimum > values["Maximum"] {
+			r.addIssue(SeverityError, CheckNonMonotone, path, line, hashFunction, "Minimum (%v) is larger than Maximum (%v).", minimum, values["Maximum"])
+		}
+
+		for i := 1; i < len(specification.percentileColumns); i++ {
+			previous, current := specification.percentileColumns[i-1], specification.percentileColumns[i]
+			if values[previous] > values[current] {
+				r.addIssue(SeverityError, CheckNonMonotone, path, line, hashFunction, "%s (%v) is larger than %s (%v).", previous, values[previous], current, values[current])
+			}
+		}
+	}
+
+	return result
+}
+
+// validateDirigentFile returns the index of each function by its HashFunction, or nil if the file cannot be checked
+func (r *ValidationReport) validateDirigentFile(path string) map[string]int {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		r.addIssue(SeverityError, CheckMissingFile, path, 0, "", "Failed to read the file - %v", err)
+		return nil
+	}
+
+	var metadata []common.DirigentMetadata
+	if err = json.Unmarshal(data, &metadata); err != nil {
+		r.addIssue(SeverityError, CheckMalformedFile, path, 0, "", "Failed to parse the file - %v", err)
+		return nil
+	}
+
+	result := make(map[string]int)
+	for i, entry := range metadata {
+		if _, found := result[entry.HashFunction]; found {
+			r.addIssue(SeverityError, CheckDuplicateFunction, path, 0, entry.HashFunction, "Function is defined more than once.")
+			continue
+		}
+		result[entry.HashFunction] = i
+
+		if entry.Image == "" {
+			r.addIssue(SeverityError, CheckInvalidValue, path, 0, entry.HashFunction, "Image is not set.")
+		}
+		if entry.Port <= 0 {
+			r.addIssue(SeverityError, CheckInvalidValue, path, 0, entry.HashFunction, "Port is not positive (%d).", entry.Port)
+		}
+	}
+
+	return result
+}
+
+// validateHashes reports functions of the invocation trace missing from the other file and vice versa
+func (r *ValidationReport) validateHashes(invocationHashes map[string]int, otherHashes map[string]int, otherFile string, severity ValidationSeverity) {
+	if otherHashes == nil {
+		return
+	}
+
+	for _, hashFunction := range sortedKeys(invocationHashes) {
+		if _, found := otherHashes[hashFunction]; !found {
+			r.addIssue(severity, CheckMismatchedHash, "invocations.csv", invocationHashes[hashFunction], hashFunction, "Function is missing from %s.", otherFile)
+		}
+	}
+
+	for _, hashFunction := range sortedKeys(otherHashes) {
+		if _, found := invocationHashes[hashFunction]; !found {
+			r.addIssue(SeverityWarning, CheckMismatchedHash, otherFile, 0, hashFunction, "Function is not in invocations.csv and will be ignored.")
+		}
+	}
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// WriteText writes a human-readable report
+func (r *ValidationReport) WriteText(w io.Writer) {
+	fmt.Fprintf(w, "Trace validation report for %s\n", r.TracePath)
+	fmt.Fprintf(w, "Functions: %d, minutes: %d, errors: %d, warnings: %d\n", r.Functions, r.Minutes, r.Errors, r.Warnings)
+
+	for _, issue := range r.Issues {
+		location := issue.File
+		if issue.Line > 0 {
+			location = fmt.Sprintf("%s:%d", issue.File, issue.Line)
+		}
+		if issue.HashFunction != "" {
+			location = fmt.Sprintf("%s (%s)", location, issue.HashFunction)
+		}
+
+		fmt.Fprintf(w, "[%s] %s %s: %s\n", strings.ToUpper(string(issue.Severity)), location, issue.Check, issue.Message)
+	}
+
+	if !r.HasErrors() {
+		fmt.Fprintln(w, "The trace is valid.")
+	}
+}
+
+// WriteJSON writes a machine-readable report
+func (r *ValidationReport) WriteJSON(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
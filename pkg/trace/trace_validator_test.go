@@ -0,0 +1,108 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package trace
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateValidTrace(t *testing.T) {
+	report := ValidateTrace("test_data", 10)
+
+	if report.HasErrors() || report.Functions != 1 || report.Minutes != 1440 {
+		t.Errorf("Unexpected validation report of a valid trace: %+v", report)
+	}
+}
+
+func TestValidateInvalidTrace(t *testing.T) {
+	report := ValidateTrace("test_data/invalid", 5)
+
+	type issueKey struct {
+		severity     ValidationSeverity
+		check        string
+		file         string
+		hashFunction string
+	}
+
+	expected := map[issueKey]int{
+		{SeverityWarning, CheckMinutesBeyond, "invocations.csv", ""}:     1,
+		{SeverityWarning, CheckZeroCount, "invocations.csv", "f2"}:       1,
+		{SeverityError, CheckNegativeValue, "invocations.csv", "f3"}:     1,
+		{SeverityError, CheckInvalidValue, "invocations.csv", "f3"}:      1,
+		{SeverityError, CheckDuplicateFunction, "invocations.csv", "f1"}: 1,
+		{SeverityError, CheckMalformedRow, "invocations.csv", ""}:        1,
+		{SeverityError, CheckZeroCount, "durations.csv", "f2"}:           1,
+		{SeverityError, CheckNonMonotone, "durations.csv", "f3"}:         2,
+		{SeverityError, CheckDuplicateFunction, "memory.csv", "f1"}:      1,
+		{SeverityWarning, CheckMismatchedHash, "durations.csv", "f5"}:    1,
+		{SeverityError, CheckMismatchedHash, "invocations.csv", "f2"}:    1,
+		{SeverityError, CheckInvalidValue, "dirigent.json", "f2"}:        1,
+		{SeverityWarning, CheckMismatchedHash, "invocations.csv", "f3"}:  1,
+	}
+
+	actual := make(map[issueKey]int)
+	for _, issue := range report.Issues {
+		actual[issueKey{issue.Severity, issue.Check, issue.File, issue.HashFunction}]++
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Unexpected validation issues: %v", actual)
+	}
+
+	if !report.HasErrors() || report.Errors != 10 || report.Warnings != 4 {
+		t.Errorf("Unexpected number of errors (%d) and warnings (%d).", report.Errors, report.Warnings)
+	}
+
+	var text bytes.Buffer
+	report.WriteText(&text)
+	if !strings.Contains(text.String(), "[ERROR] invocations.csv:5 (f1) duplicate_function") {
+		t.Error("Duplicate function is not in the text report.")
+	}
+
+	jsonPath := filepath.Join(t.TempDir(), "report.json")
+	if err := report.WriteJSON(jsonPath); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := os.ReadFile(jsonPath)
+	var parsed ValidationReport
+	if err := json.Unmarshal(data, &parsed); err != nil || !reflect.DeepEqual(&parsed, report) {
+		t.Error("JSON report does not match the validation report.")
+	}
+}
+
+func TestValidateMissingTrace(t *testing.T) {
+	report := ValidateTrace(t.TempDir(), 0)
+
+	if report.Errors != 3 {
+		t.Errorf("Expected all three trace files to be reported missing, got %d errors.", report.Errors)
+	}
+}
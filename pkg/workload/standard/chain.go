@@ -0,0 +1,163 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package standard
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	util "github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/workload/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// chainedVHiveMetadata returns the vHive metadata of the invocation if the function has to invoke next stages
+func chainedVHiveMetadata(ctx context.Context) (util.VHiveMetadata, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(md.Get(util.VHiveMetadataKey)) == 0 {
+		return util.VHiveMetadata{}, false
+	}
+
+	vhm, err := util.ParseVHiveMetadata([]byte(md.Get(util.VHiveMetadataKey)[0]))
+	if err != nil {
+		log.Warnf("Failed to parse vHive metadata - %v", err)
+		return util.VHiveMetadata{}, false
+	}
+
+	return vhm, len(vhm.Next) > 0
+}
+
+// invokeChainedStages invokes the next stages in parallel and returns the records of all the stages invoked after the
+// function, where each stage precedes the ones it invoked
+func invokeChainedStages(ctx context.Context, vhm util.VHiveMetadata) []util.ChainHop {
+	hops := make([][]util.ChainHop, len(vhm.Next))
+
+	wg := sync.WaitGroup{}
+	for i, stage := range vhm.Next {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			hops[i] = invokeChainedStage(ctx, vhm, stage)
+		}()
+	}
+	wg.Wait()
+
+	var result []util.ChainHop
+	for _, stageHops := range hops {
+		result = append(result, stageHops...)
+	}
+
+	return result
+}
+
+func invokeChainedStage(ctx context.Context, vhm util.VHiveMetadata, stage *util.ChainedStage) []util.ChainHop {
+	hop := util.ChainHop{
+		Stage:        stage.Stage,
+		ParentStage:  vhm.Stage,
+		PayloadBytes: stage.PayloadBytes,
+	}
+
+	start := time.Now()
+	hop.StartTime = start.UnixMicro()
+
+	dialOptions := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	if stage.Authority != "" {
+		dialOptions = append(dialOptions, grpc.WithAuthority(stage.Authority))
+	}
+
+	conn, err := grpc.NewClient(stage.Endpoint, dialOptions...)
+	if err != nil {
+		log.Warnf("Failed to connect to stage %s - %v", stage.Stage, err)
+
+		hop.ResponseTime = time.Since(start).Microseconds()
+		return []util.ChainHop{hop}
+	}
+	defer conn.Close()
+
+	message := "nothing"
+	if stage.PayloadBytes > 0 {
+		message = strings.Repeat("x", stage.PayloadBytes)
+	}
+
+	// the deadline of the invocation of the function also applies to the stages after it
+	outgoing := metadata.AppendToOutgoingContext(ctx, util.VHiveMetadataKey,
+		string(util.MakeChainedVHiveMetadata(vhm.WorkflowId, vhm.InvocationId, time.Now().UTC(), stage.Stage, stage.Next)))
+
+	var trailer metadata.MD
+	response, err := proto.NewExecutorClient(conn).Execute(outgoing, &proto.FaasRequest{
+		Message:           message,
+		RuntimeInMilliSec: stage.RuntimeInMilliSec,
+		MemoryInMebiBytes: stage.MemoryInMebiBytes,
+	}, grpc.Trailer(&trailer))
+	hop.ResponseTime = time.Since(start).Microseconds()
+
+	if err != nil {
+		log.Warnf("Failed to invoke stage %s - %v", stage.Stage, err)
+		return []util.ChainHop{hop}
+	}
+
+	hop.Success = true
+	hop.DurationInMicroSec = response.DurationInMicroSec
+	hop.MemoryUsageInKb = response.MemoryUsageInKb
+	hop.Message = response.Message
+
+	return append([]util.ChainHop{hop}, receivedChainHops(trailer)...)
+}
+
+// reportChainHops returns the records of the stages invoked after the function to its caller
+func reportChainHops(ctx context.Context, hops []util.ChainHop) {
+	data, err := json.Marshal(hops)
+	if err != nil {
+		log.Warnf("Failed to marshal the records of chained stages - %v", err)
+		return
+	}
+
+	if err = grpc.SetTrailer(ctx, metadata.Pairs(util.ChainHopsKey, string(data))); err != nil {
+		log.Warnf("Failed to report the records of chained stages - %v", err)
+	}
+}
+
+// receivedChainHops returns the records of the stages invoked after a function, as reported in its trailer
+func receivedChainHops(trailer metadata.MD) []util.ChainHop {
+	values := trailer.Get(util.ChainHopsKey)
+	if len(values) == 0 {
+		return nil
+	}
+
+	var hops []util.ChainHop
+	if err := json.Unmarshal([]byte(values[0]), &hops); err != nil {
+		log.Warnf("Failed to unmarshal the records of chained stages - %v", err)
+		return nil
+	}
+
+	return hops
+}
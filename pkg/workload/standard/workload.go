@@ -58,7 +58,7 @@ type funcServer struct {
 	proto.UnimplementedExecutorServer
 }
 
-func (s *funcServer) Execute(_ context.Context, req *proto.FaasRequest) (*proto.FaasReply, error) {
+func (s *funcServer) Execute(ctx context.Context, req *proto.FaasRequest) (*proto.FaasReply, error) {
 	var msg string
 	start := time.Now()
 
@@ -81,11 +81,18 @@ func (s *funcServer) Execute(_ context.Context, req *proto.FaasRequest) (*proto.
 		msg = fmt.Sprintf("OK - EMPTY - %s", hostname)
 	}
 
-	return &proto.FaasReply{
+	reply := &proto.FaasReply{
 		Message:            msg,
 		DurationInMicroSec: uint32(time.Since(start).Microseconds()),
 		MemoryUsageInKb:    req.MemoryInMebiBytes * 1024,
-	}, nil
+	}
+
+	// in server-side chaining, the function invokes the next stages of the DAG once it has completed
+	if vhm, ok := chainedVHiveMetadata(ctx); ok {
+		reportChainHops(ctx, invokeChainedStages(ctx, vhm))
+	}
+
+	return reply, nil
 }
 
 func readEnvironmentalVariables() {
@@ -35,6 +35,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/config"
 	spec "github.com/vhive-serverless/loader/pkg/generator"
 	trace "github.com/vhive-serverless/loader/pkg/trace"
 )
@@ -42,6 +43,7 @@ import (
 var (
 	scale           = flag.String("scale", "millisecond", "Scale of the timeline to generate, one of [millisecond, minute]")
 	tracePath       = flag.String("tracePath", "data/traces/", "Path to folder where the trace is located")
+	traceFormat     = flag.String("traceFormat", trace.DefaultTraceFormat, "Format of the trace, e.g., azure_2019, azure_2021, huawei, alibaba, csv")
 	outputFile      = flag.String("outputFile", "output.csv", "Path to output file")
 	duration        = flag.Int("duration", 1440, "Duration of the traces in minutes")
 	cpuQuota        = flag.Bool("cpuQuota", true, "Whether to use the CPU quota or not")
@@ -109,8 +111,10 @@ func generateLoad(outputFilename string, millisecondScale bool) {
 	}
 	writer := make(chan interface{}, 1000)
 
-	traceParser := trace.NewAzureParser(*tracePath, *duration)
-	functions := traceParser.Parse()
+	functions := trace.ParseTrace(&config.LoaderConfiguration{
+		TracePath:   *tracePath,
+		TraceFormat: *traceFormat,
+	}, *duration, common.MinuteGranularity)
 
 	log.Infof("Traces contain the following %d functions:\n", len(functions))
 
@@ -128,6 +132,11 @@ func generateLoad(outputFilename string, millisecondScale bool) {
 	specGenerator := spec.NewSpecificationGenerator(*randSeed)
 
 	for i, function := range functions {
+		if function.Specification != nil {
+			// the trace contains the arrival time of each invocation
+			continue
+		}
+
 		spec := specGenerator.GenerateInvocationData(function, iatType, false, common.MinuteGranularity)
 		functions[i].Specification = spec
 	}
@@ -143,53 +152,54 @@ func generateLoad(outputFilename string, millisecondScale bool) {
 
 func generateFunctionTimeline(function *common.Function, writer chan interface{}, wg *sync.WaitGroup, millisecondScale bool) {
 	defer wg.Done()
-	minuteIndex, invocationIndex := 0, 0
+	minuteIndex, invocationIndex, iatIndex := 0, 0, 0
 	runtimes, memory, cpuSum, memoryUsage := 0, 0, 0, 0
+	timestamp := 0.0
 
 	IAT, runtimeSpecification := function.Specification.IAT, function.Specification.RuntimeSpecification
+	perMinuteCount := function.Specification.PerMinuteCount
 
 	for {
-		if minuteIndex >= *duration {
+		if minuteIndex >= *duration || minuteIndex >= len(perMinuteCount) {
 			break
-		} else if function.InvocationStats.Invocations[minuteIndex] == 0 {
+		} else if perMinuteCount[minuteIndex] == 0 {
 			minuteIndex++
 			invocationIndex = 0
 			continue
 		}
-		sum := 0.0
-		for i := 0; i < invocationIndex; i++ {
-			sum += IAT[minuteIndex][i]
-		}
+		// IATs are relative to the previous invocation, starting from the beginning of the trace
+		timestamp += IAT[iatIndex]
 
 		var duration, cpu int
 		if *cpuQuota {
 			cpu = trace.ConvertMemoryToCpu(int(function.MemoryStats.Percentile100))
-			duration = int((float64(runtimeSpecification[minuteIndex][invocationIndex].Runtime) / float64(cpu)) * 1000)
+			duration = int((float64(runtimeSpecification[iatIndex].Runtime) / float64(cpu)) * 1000)
 		} else {
-			duration = runtimeSpecification[minuteIndex][invocationIndex].Runtime
+			duration = runtimeSpecification[iatIndex].Runtime
 			cpu = 1 * 1000
 		}
 
 		if millisecondScale {
 			// Write the millisecond scale timeline
 			writer <- loaderRecord{
-				Millisecond:  int((time.Duration(minuteIndex)*time.Minute + time.Duration(sum)*time.Microsecond) / time.Millisecond),
+				Millisecond:  int(time.Duration(timestamp) * time.Microsecond / time.Millisecond),
 				FunctionHash: function.InvocationStats.HashApp,
 				Runtime:      duration,
-				MemoryUsage:  runtimeSpecification[minuteIndex][invocationIndex].Memory,
+				MemoryUsage:  runtimeSpecification[iatIndex].Memory,
 				Memory:       int(function.MemoryStats.Percentile100),
 				Cpu:          cpu,
 			}
 		} else {
 			// Add the millisecond data to list, to be averaged later
 			runtimes += duration
-			memoryUsage += runtimeSpecification[minuteIndex][invocationIndex].Memory * duration
+			memoryUsage += runtimeSpecification[iatIndex].Memory * duration
 			cpuSum += cpu
 			memory += int(function.MemoryStats.Percentile100) * duration
 		}
 
 		invocationIndex++
-		if function.InvocationStats.Invocations[minuteIndex] == invocationIndex {
+		iatIndex++
+		if perMinuteCount[minuteIndex] == invocationIndex {
 			if !millisecondScale {
 				// Generated one minute of the trace, write the average
 				writer <- minuteTimelineRecord{
@@ -1,6 +1,6 @@
 module github.com/vhive-serverless/sampler/tools/generateTimeline
 
-go 1.20
+go 1.22.7
 
 require (
 	github.com/gocarina/gocsv v0.0.0-20230616125104-99d496ca653d
@@ -8,4 +8,12 @@ require (
 	github.com/vhive-serverless/loader v0.0.0-20230908095153-17085691c132
 )
 
-require golang.org/x/sys v0.9.0 // indirect
+require (
+	github.com/containerd/log v0.1.0 // indirect
+	golang.org/x/sys v0.29.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+	k8s.io/apimachinery v0.30.2 // indirect
+	k8s.io/utils v0.0.0-20230726121419-3b25d923346b // indirect
+)
+
+replace github.com/vhive-serverless/loader => ../..
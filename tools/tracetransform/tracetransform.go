@@ -0,0 +1,141 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"flag"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/sampler"
+)
+
+var (
+	inputPaths    = flag.String("input", "data/traces/example", "Comma-separated directories of Azure-format traces, which are merged if there is more than one")
+	outputPath    = flag.String("output", "data/traces/transformed", "Directory to write the transformed trace to")
+	triggers      = flag.String("trigger", "", "Comma-separated triggers of the functions to keep (all if empty)")
+	owners        = flag.String("owner", "", "Comma-separated HashOwner of the functions to keep (all if empty)")
+	apps          = flag.String("app", "", "Comma-separated HashApp of the functions to keep (all if empty)")
+	shift         = flag.Int("shift", 0, "Minutes to shift the trace by, where negative values drop the first minutes")
+	cropStart     = flag.Int("cropStart", 0, "First minute of the trace to keep")
+	cropDuration  = flag.Int("cropDuration", 0, "Number of minutes of the trace to keep starting at cropStart (all if zero)")
+	scale         = flag.Float64("scale", 1, "Factor to multiply the number of invocations in each minute by")
+	invocationCap = flag.Int("cap", 0, "Maximum number of invocations of a function in a minute (no limit if zero)")
+	runtime       = flag.String("runtime", "", "Replace runtimes with constant:V, uniform:MIN:MAX, normal:MEAN:STDDEV or exponential:MEAN in ms")
+	memory        = flag.String("memory", "", "Replace memory with constant:V, uniform:MIN:MAX, normal:MEAN:STDDEV or exponential:MEAN in MiB")
+	seed          = flag.Int64("seed", 42, "Seed for scaling and for sampling runtime and memory")
+	verbosity     = flag.String("verbosity", "info", "Logging verbosity - choose from [info, debug]")
+)
+
+func main() {
+	flag.Parse()
+
+	log.SetFormatter(&log.TextFormatter{
+		TimestampFormat: time.StampMilli,
+		FullTimestamp:   true,
+	})
+	log.SetOutput(os.Stdout)
+	if *verbosity == "debug" {
+		log.SetLevel(log.DebugLevel)
+	}
+
+	var traces [][]*common.Function
+	for _, path := range splitList(*inputPaths) {
+		traces = append(traces, readTrace(path))
+	}
+
+	functions := mergeTraces(traces)
+	functions = filterFunctions(functions, splitList(*triggers), splitList(*owners), splitList(*apps))
+
+	if *shift != 0 {
+		shiftTrace(functions, *shift)
+	}
+	if *cropStart != 0 || *cropDuration != 0 {
+		duration := *cropDuration
+		if duration == 0 {
+			duration = traceLength(functions) - *cropStart
+		}
+
+		cropTrace(functions, *cropStart, common.MaxOf(duration, 0))
+	}
+
+	gen := rand.New(rand.NewSource(*seed))
+
+	if *scale != 1 {
+		if *scale < 0 {
+			log.Fatal("Scaling factor cannot be negative.")
+		}
+
+		scaleInvocations(functions, *scale, gen)
+	}
+	if *invocationCap > 0 {
+		capInvocations(functions, *invocationCap)
+	}
+
+	if *runtime != "" {
+		distribution, err := parseDistribution(*runtime)
+		if err != nil {
+			log.Fatalf("Invalid runtime distribution - %v", err)
+		}
+
+		replaceRuntime(functions, distribution, gen)
+	}
+	if *memory != "" {
+		distribution, err := parseDistribution(*memory)
+		if err != nil {
+			log.Fatalf("Invalid memory distribution - %v", err)
+		}
+
+		replaceMemory(functions, distribution, gen)
+	}
+
+	sampler.WriteTrace(functions, *outputPath)
+
+	log.Infof("Wrote %d functions with %d minutes of invocations to %s", len(functions), traceLength(functions), *outputPath)
+}
+
+func splitList(list string) []string {
+	var result []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
+
+func traceLength(functions []*common.Function) int {
+	result := 0
+	for _, function := range functions {
+		result = common.MaxOf(result, len(function.InvocationStats.Invocations))
+	}
+
+	return result
+}
@@ -0,0 +1,216 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/sampler"
+	"github.com/vhive-serverless/loader/pkg/trace"
+)
+
+const testTracePath = "../../pkg/trace/test_data"
+
+func createTestFunction(owner string, app string, hashFunction string, trigger string, invocations []int) *common.Function {
+	return &common.Function{
+		InvocationStats: &common.FunctionInvocationStats{
+			HashOwner:    owner,
+			HashApp:      app,
+			HashFunction: hashFunction,
+			Trigger:      trigger,
+			Invocations:  invocations,
+		},
+		RuntimeStats: &common.FunctionRuntimeStats{HashFunction: hashFunction, Count: 10, Average: 100},
+		MemoryStats:  &common.FunctionMemoryStats{HashFunction: hashFunction, Count: 10, Average: 128},
+	}
+}
+
+func TestMergeTraces(t *testing.T) {
+	first, second := readTrace(testTracePath), readTrace(testTracePath)
+	hashFunction := first[0].InvocationStats.HashFunction
+
+	merged := mergeTraces([][]*common.Function{first, second})
+	if len(merged) != 2 {
+		t.Fatalf("Expected 2 functions, got %d.", len(merged))
+	}
+
+	if merged[0].InvocationStats.HashFunction != hashFunction ||
+		merged[1].InvocationStats.HashFunction != hashFunction+"-1" ||
+		merged[1].RuntimeStats.HashFunction != hashFunction+"-1" ||
+		merged[1].MemoryStats.HashFunction != hashFunction+"-1" {
+
+		t.Error("Duplicate function has not been renamed.")
+	}
+
+	if first[0].RuntimeStats.HashFunction != hashFunction {
+		t.Error("Renaming must not modify statistics shared with other functions.")
+	}
+}
+
+func TestFilterFunctions(t *testing.T) {
+	functions := []*common.Function{
+		createTestFunction("o1", "a1", "f1", "http", []int{1}),
+		createTestFunction("o1", "a2", "f2", "timer", []int{1}),
+		createTestFunction("o2", "a3", "f3", "http", []int{1}),
+	}
+
+	tests := []struct {
+		testName string
+		triggers []string
+		owners   []string
+		apps     []string
+		expected []string
+	}{
+		{testName: "no_filter", expected: []string{"f1", "f2", "f3"}},
+		{testName: "trigger", triggers: []string{"HTTP"}, expected: []string{"f1", "f3"}},
+		{testName: "owner", owners: []string{"o1"}, expected: []string{"f1", "f2"}},
+		{testName: "trigger_and_owner", triggers: []string{"http"}, owners: []string{"o1"}, expected: []string{"f1"}},
+		{testName: "app", apps: []string{"a2", "a3"}, expected: []string{"f2", "f3"}},
+		{testName: "no_match", apps: []string{"a4"}, expected: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			var result []string
+			for _, function := range filterFunctions(functions, test.triggers, test.owners, test.apps) {
+				result = append(result, function.InvocationStats.HashFunction)
+			}
+
+			if !reflect.DeepEqual(result, test.expected) {
+				t.Errorf("Expected %v, got %v.", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestShiftAndCropTrace(t *testing.T) {
+	tests := []struct {
+		testName string
+		apply    func(functions []*common.Function)
+		expected []int
+	}{
+		{testName: "shift_right", apply: func(f []*common.Function) { shiftTrace(f, 2) }, expected: []int{0, 0, 1, 2, 3, 4}},
+		{testName: "shift_left", apply: func(f []*common.Function) { shiftTrace(f, -1) }, expected: []int{2, 3, 4}},
+		{testName: "shift_everything", apply: func(f []*common.Function) { shiftTrace(f, -10) }, expected: []int{}},
+		{testName: "crop", apply: func(f []*common.Function) { cropTrace(f, 1, 2) }, expected: []int{2, 3}},
+		{testName: "crop_beyond", apply: func(f []*common.Function) { cropTrace(f, 3, 3) }, expected: []int{4, 0, 0}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			functions := []*common.Function{createTestFunction("o", "a", "f", "http", []int{1, 2, 3, 4})}
+			test.apply(functions)
+
+			if !reflect.DeepEqual(functions[0].InvocationStats.Invocations, test.expected) {
+				t.Errorf("Expected %v, got %v.", test.expected, functions[0].InvocationStats.Invocations)
+			}
+		})
+	}
+}
+
+func TestScaleAndCapInvocations(t *testing.T) {
+	invocations := make([]int, 1000)
+	for i := range invocations {
+		invocations[i] = 3
+	}
+
+	functions := []*common.Function{createTestFunction("o", "a", "f", "http", invocations)}
+	scaleInvocations(functions, 0.5, rand.New(rand.NewSource(42)))
+
+	total := 0
+	for _, count := range functions[0].InvocationStats.Invocations {
+		if count != 1 && count != 2 {
+			t.Fatalf("Unexpected scaled invocation count %d.", count)
+		}
+
+		total += count
+	}
+
+	if total < 1400 || total > 1600 {
+		t.Errorf("Expected around 1500 invocations after scaling, got %d.", total)
+	}
+
+	capInvocations(functions, 1)
+	for _, count := range functions[0].InvocationStats.Invocations {
+		if count != 1 {
+			t.Fatalf("Invocation count %d exceeds the cap.", count)
+		}
+	}
+}
+
+func TestParseDistribution(t *testing.T) {
+	gen := rand.New(rand.NewSource(42))
+
+	constant, err := parseDistribution("constant:250")
+	if err != nil || constant.Sample(gen) != 250 {
+		t.Error("Unexpected constant distribution.")
+	}
+
+	uniform, err := parseDistribution("uniform:10:20")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		if value := uniform.Sample(gen); value < 10 || value > 20 {
+			t.Fatalf("Sample %f is out of the range of the uniform distribution.", value)
+		}
+	}
+
+	for _, invalid := range []string{"gamma:1:2", "normal:1", "exponential:x", ""} {
+		if _, err = parseDistribution(invalid); err == nil {
+			t.Errorf("Distribution %q should be rejected.", invalid)
+		}
+	}
+}
+
+func TestTransformedTraceIsReadable(t *testing.T) {
+	functions := mergeTraces([][]*common.Function{readTrace(testTracePath), readTrace(testTracePath)})
+	cropTrace(functions, 0, 10)
+	replaceRuntime(functions, &Distribution{Name: "constant", Parameters: []float64{150}}, rand.New(rand.NewSource(42)))
+	replaceMemory(functions, &Distribution{Name: "constant", Parameters: []float64{256}}, rand.New(rand.NewSource(42)))
+
+	outputPath := t.TempDir()
+	sampler.WriteTrace(functions, outputPath)
+
+	if report := trace.ValidateTrace(outputPath, 10); report.HasErrors() {
+		t.Errorf("Transformed trace is not valid: %+v", report.Issues)
+	}
+
+	parsed := trace.NewAzureParser(outputPath, 10).Parse()
+	if len(parsed) != 2 {
+		t.Fatalf("Expected 2 functions, got %d.", len(parsed))
+	}
+
+	for i, function := range parsed {
+		if !reflect.DeepEqual(function.InvocationStats.Invocations, functions[i].InvocationStats.Invocations) ||
+			function.RuntimeStats.Percentile50 != 150 || function.MemoryStats.Percentile99 != 256 {
+
+			t.Errorf("Function %d has not been written correctly.", i)
+		}
+	}
+}
@@ -0,0 +1,330 @@
+/*
+ * MIT License
+ *
+ * Copyright (c) 2023 EASL and the vHive community
+ *
+ * Permission is hereby granted, free of charge, to any person obtaining a copy
+ * of this software and associated documentation files (the "Software"), to deal
+ * in the Software without restriction, including without limitation the rights
+ * to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ * copies of the Software, and to permit persons to whom the Software is
+ * furnished to do so, subject to the following conditions:
+ *
+ * The above copyright notice and this permission notice shall be included in all
+ * copies or substantial portions of the Software.
+ *
+ * THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ * IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ * FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ * AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ * LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ * OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+ * SOFTWARE.
+ */
+
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vhive-serverless/loader/pkg/common"
+	"github.com/vhive-serverless/loader/pkg/sampler"
+	"github.com/vhive-serverless/loader/pkg/trace"
+)
+
+// readTrace parses all the minutes of the Azure-format trace in the directory
+func readTrace(tracePath string) []*common.Function {
+	functions := trace.NewAzureParser(tracePath, traceMinutes(filepath.Join(tracePath, "invocations.csv"))).Parse()
+	sampler.AttachDirigentMetadata(functions, tracePath, "")
+
+	return functions
+}
+
+// traceMinutes returns the number of minute columns of the invocation trace
+func traceMinutes(path string) int {
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("Failed to open invocation trace %s - %v", path, err)
+	}
+	defer file.Close()
+
+	header, err := csv.NewReader(file).Read()
+	if err != nil {
+		log.Fatalf("Failed to read the header of invocation trace %s - %v", path, err)
+	}
+
+	for i, column := range header {
+		if strings.EqualFold(column, "Trigger") {
+			return len(header) - i - 1
+		}
+	}
+
+	return len(header) - 3
+}
+
+// mergeTraces concatenates the functions of the traces. Functions whose HashFunction has already been used by a
+// previous trace get the index of their trace appended, so that their duration and memory statistics stay separate.
+func mergeTraces(traces [][]*common.Function) []*common.Function {
+	var result []*common.Function
+	used := make(map[string]bool)
+
+	for i, functions := range traces {
+		renamed := make(map[string]string)
+
+		for _, function := range functions {
+			hashFunction := function.InvocationStats.HashFunction
+
+			// functions of the same trace sharing a HashFunction share their statistics, so they are renamed together
+			newHashFunction, ok := renamed[hashFunction]
+			if !ok {
+				newHashFunction = hashFunction
+				for suffix := i; used[newHashFunction]; suffix++ {
+					newHashFunction = fmt.Sprintf("%s-%d", hashFunction, suffix)
+				}
+
+				renamed[hashFunction] = newHashFunction
+			}
+
+			if newHashFunction != hashFunction {
+				log.Debugf("Renaming function %s of trace %d to %s", hashFunction, i, newHashFunction)
+				setHashFunction(function, newHashFunction)
+			}
+
+			result = append(result, function)
+		}
+
+		for _, hashFunction := range renamed {
+			used[hashFunction] = true
+		}
+	}
+
+	return result
+}
+
+func setHashFunction(function *common.Function, hashFunction string) {
+	invocationStats := *function.InvocationStats
+	invocationStats.HashFunction = hashFunction
+	function.InvocationStats = &invocationStats
+
+	if function.RuntimeStats != nil {
+		runtimeStats := *function.RuntimeStats
+		runtimeStats.HashFunction = hashFunction
+		function.RuntimeStats = &runtimeStats
+	}
+
+	if function.MemoryStats != nil {
+		memoryStats := *function.MemoryStats
+		memoryStats.HashFunction = hashFunction
+		function.MemoryStats = &memoryStats
+	}
+}
+
+// filterFunctions keeps the functions matching all the non-empty lists of triggers, owners and apps
+func filterFunctions(functions []*common.Function, triggers []string, owners []string, apps []string) []*common.Function {
+	matches := func(values []string, value string) bool {
+		if len(values) == 0 {
+			return true
+		}
+
+		for _, v := range values {
+			if strings.EqualFold(v, value) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	var result []*common.Function
+	for _, function := range functions {
+		stats := function.InvocationStats
+
+		if matches(triggers, stats.Trigger) && matches(owners, stats.HashOwner) && matches(apps, stats.HashApp) {
+			result = append(result, function)
+		}
+	}
+
+	return result
+}
+
+// shiftTrace moves the invocations by the number of minutes, where a positive shift prepends minutes without
+// invocations and a negative one drops the first minutes of the trace
+func shiftTrace(functions []*common.Function, minutes int) {
+	for _, function := range functions {
+		invocations := function.InvocationStats.Invocations
+
+		if minutes >= 0 {
+			function.InvocationStats.Invocations = append(make([]int, minutes), invocations...)
+		} else {
+			function.InvocationStats.Invocations = append([]int{}, invocations[common.MinOf(-minutes, len(invocations)):]...)
+		}
+	}
+}
+
+// cropTrace keeps duration minutes of the trace starting at minute start, padding with zeros beyond the trace
+func cropTrace(functions []*common.Function, start int, duration int) {
+	for _, function := range functions {
+		invocations := function.InvocationStats.Invocations
+		cropped := make([]int, duration)
+
+		for minute := 0; minute < duration && start+minute < len(invocations); minute++ {
+			cropped[minute] = invocations[start+minute]
+		}
+
+		function.InvocationStats.Invocations = cropped
+	}
+}
+
+// scaleInvocations multiplies the number of invocations in each minute by the factor. Fractional invocations are
+// rounded stochastically so that the expected total number of invocations is scaled exactly.
+func scaleInvocations(functions []*common.Function, factor float64, gen *rand.Rand) {
+	for _, function := range functions {
+		for minute, count := range function.InvocationStats.Invocations {
+			scaled := float64(count) * factor
+			whole := math.Floor(scaled)
+
+			if gen.Float64() < scaled-whole {
+				whole++
+			}
+
+			function.InvocationStats.Invocations[minute] = int(whole)
+		}
+	}
+}
+
+// capInvocations limits the number of invocations in each minute
+func capInvocations(functions []*common.Function, limit int) {
+	for _, function := range functions {
+		for minute, count := range function.InvocationStats.Invocations {
+			function.InvocationStats.Invocations[minute] = common.MinOf(count, limit)
+		}
+	}
+}
+
+// Distribution of values replacing the runtime or memory of each function
+type Distribution struct {
+	Name       string
+	Parameters []float64
+}
+
+// parseDistribution parses "constant:V", "uniform:MIN:MAX", "normal:MEAN:STDDEV" or "exponential:MEAN"
+func parseDistribution(specification string) (*Distribution, error) {
+	fields := strings.Split(specification, ":")
+
+	expectedParameters := map[string]int{
+		"constant":    1,
+		"uniform":     2,
+		"normal":      2,
+		"exponential": 1,
+	}
+
+	name := strings.ToLower(fields[0])
+	count, ok := expectedParameters[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported distribution %s", fields[0])
+	}
+	if len(fields)-1 != count {
+		return nil, fmt.Errorf("distribution %s expects %d parameters, got %d", name, count, len(fields)-1)
+	}
+
+	distribution := &Distribution{Name: name}
+	for _, field := range fields[1:] {
+		value, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid parameter %s of distribution %s", field, name)
+		}
+
+		distribution.Parameters = append(distribution.Parameters, value)
+	}
+
+	return distribution, nil
+}
+
+// Sample draws a non-negative value from the distribution
+func (d *Distribution) Sample(gen *rand.Rand) float64 {
+	var value float64
+
+	switch d.Name {
+	case "constant":
+		value = d.Parameters[0]
+	case "uniform":
+		value = d.Parameters[0] + gen.Float64()*(d.Parameters[1]-d.Parameters[0])
+	case "normal":
+		value = d.Parameters[0] + gen.NormFloat64()*d.Parameters[1]
+	case "exponential":
+		value = gen.ExpFloat64() * d.Parameters[0]
+	}
+
+	return math.Max(value, 0)
+}
+
+// replaceRuntime gives each function a runtime drawn from the distribution, which all its percentiles are set to
+func replaceRuntime(functions []*common.Function, distribution *Distribution, gen *rand.Rand) {
+	for _, function := range functions {
+		runtime := distribution.Sample(gen)
+		stats := function.InvocationStats
+
+		count := 1.0
+		if function.RuntimeStats != nil && function.RuntimeStats.Count > 0 {
+			count = function.RuntimeStats.Count
+		}
+
+		function.RuntimeStats = &common.FunctionRuntimeStats{
+			HashOwner:    stats.HashOwner,
+			HashApp:      stats.HashApp,
+			HashFunction: stats.HashFunction,
+
+			Average: runtime,
+			Count:   count,
+			Minimum: runtime,
+			Maximum: runtime,
+
+			Percentile0:   runtime,
+			Percentile1:   runtime,
+			Percentile25:  runtime,
+			Percentile50:  runtime,
+			Percentile75:  runtime,
+			Percentile99:  runtime,
+			Percentile100: runtime,
+		}
+	}
+}
+
+// replaceMemory gives each function a memory drawn from the distribution, which all its percentiles are set to
+func replaceMemory(functions []*common.Function, distribution *Distribution, gen *rand.Rand) {
+	for _, function := range functions {
+		memory := distribution.Sample(gen)
+		stats := function.InvocationStats
+
+		count := 1.0
+		if function.MemoryStats != nil && function.MemoryStats.Count > 0 {
+			count = function.MemoryStats.Count
+		}
+
+		function.MemoryStats = &common.FunctionMemoryStats{
+			HashOwner:    stats.HashOwner,
+			HashApp:      stats.HashApp,
+			HashFunction: stats.HashFunction,
+
+			Count:   count,
+			Average: memory,
+
+			Percentile1:   memory,
+			Percentile5:   memory,
+			Percentile25:  memory,
+			Percentile50:  memory,
+			Percentile75:  memory,
+			Percentile95:  memory,
+			Percentile99:  memory,
+			Percentile100: memory,
+		}
+	}
+}